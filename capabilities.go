@@ -0,0 +1,96 @@
+package webcam
+
+import (
+	"fmt"
+	"sort"
+)
+
+// Full report of what the device advertises: every format with its
+// frame sizes and frame intervals, plus controls and inputs.
+// Can be serialized with encoding/json, e.g. to attach to a bug report
+// or to compare firmware versions.
+type CapabilityMatrix struct {
+	Driver   string
+	Card     string
+	BusInfo  string
+	Version  string
+	Formats  []FormatCapability
+	Controls []ControlEntry
+	Inputs   []Input
+}
+
+// Image format together with frame sizes supported for it
+type FormatCapability struct {
+	PixelFormat PixelFormat
+	FourCC      string
+	Description string
+	// Combination of V4L2_FMT_FLAG_* values
+	Flags      uint32
+	FrameSizes []FrameSizeCapability
+}
+
+// Frame size together with frame intervals supported for it.
+// For stepwise and continuous sizes intervals are reported for the
+// maximum and the minimum size only, intervals of sizes in between
+// are not enumerated and may differ from both
+type FrameSizeCapability struct {
+	FrameSize
+	FrameIntervals []FrameInterval
+	// Intervals for the minimum size, set only for
+	// stepwise and continuous sizes
+	MinSizeFrameIntervals []FrameInterval `json:",omitempty"`
+}
+
+// Walks all formats, frame sizes, frame intervals, controls and inputs
+// advertised by the device and returns them as a single report.
+// See FrameSizeCapability for intervals of stepwise and continuous sizes.
+// Formats and controls are sorted by their codes, so reports of
+// the same device can be compared directly
+func (w *Webcam) GetCapabilityMatrix() (*CapabilityMatrix, error) {
+	caps, err := getCapability(w.fd)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &CapabilityMatrix{
+		Driver:  CToGoString(caps.driver[:]),
+		Card:    CToGoString(caps.card[:]),
+		BusInfo: CToGoString(caps.bus_info[:]),
+		Version: fmt.Sprintf("%d.%d.%d", caps.version>>16, (caps.version>>8)&0xff, caps.version&0xff),
+		Formats: make([]FormatCapability, 0),
+		Inputs:  w.GetInputs(),
+	}
+
+	formats := w.GetFormatDescriptions()
+	sort.Slice(formats, func(i, j int) bool { return formats[i].Format < formats[j].Format })
+
+	for _, d := range formats {
+		f := d.Format
+		fc := FormatCapability{
+			PixelFormat: f,
+			FourCC:      f.String(),
+			Description: d.Description,
+			Flags:       d.Flags,
+			FrameSizes:  make([]FrameSizeCapability, 0),
+		}
+		for _, size := range w.GetSupportedFrameSizes(f) {
+			sc := FrameSizeCapability{
+				FrameSize:      size,
+				FrameIntervals: w.GetSupportedFrameIntervals(f, size.MaxWidth, size.MaxHeight),
+			}
+			if size.MinWidth != size.MaxWidth || size.MinHeight != size.MaxHeight {
+				sc.MinSizeFrameIntervals = w.GetSupportedFrameIntervals(f, size.MinWidth, size.MinHeight)
+			}
+			fc.FrameSizes = append(fc.FrameSizes, sc)
+		}
+		m.Formats = append(m.Formats, fc)
+	}
+
+	m.Controls = make([]ControlEntry, 0)
+	for _, class := range w.GetControls() {
+		m.Controls = append(m.Controls, class.Controls...)
+	}
+	sort.Slice(m.Controls, func(i, j int) bool { return m.Controls[i].ID < m.Controls[j].ID })
+
+	return m, nil
+}
@@ -0,0 +1,209 @@
+package webcam
+
+// Values of V4L2_CID_EXPOSURE_AUTO control
+type ExposureMode int32
+
+const (
+	ExposureAuto             ExposureMode = 0
+	ExposureManual           ExposureMode = 1
+	ExposureShutterPriority  ExposureMode = 2
+	ExposureAperturePriority ExposureMode = 3
+)
+
+// Values of V4L2_CID_POWER_LINE_FREQUENCY control
+type PowerLineFrequency int32
+
+const (
+	PowerLineFrequencyDisabled PowerLineFrequency = 0
+	PowerLineFrequency50Hz     PowerLineFrequency = 1
+	PowerLineFrequency60Hz     PowerLineFrequency = 2
+	PowerLineFrequencyAuto     PowerLineFrequency = 3
+)
+
+// Get the value of a control, returns ErrControlUnsupported
+// if the device doesn't have it
+func (w *Webcam) getNamedControl(id uint32) (int32, error) {
+	if !supportsControl(w.fd, id) {
+		return 0, ErrControlUnsupported
+	}
+	return getControl(w.fd, id)
+}
+
+// Set a control, returns ErrControlUnsupported
+// if the device doesn't have it
+func (w *Webcam) setNamedControl(id uint32, value int32) error {
+	if !supportsControl(w.fd, id) {
+		return ErrControlUnsupported
+	}
+	_, err := w.ApplyControl(ControlID(id), value)
+	return err
+}
+
+func (w *Webcam) getBoolControl(id uint32) (bool, error) {
+	v, err := w.getNamedControl(id)
+	return v != 0, err
+}
+
+func (w *Webcam) setBoolControl(id uint32, val bool) error {
+	v := int32(0)
+	if val {
+		v = 1
+	}
+	return w.setNamedControl(id, v)
+}
+
+// Sets automatic white balance correction
+func (w *Webcam) SetAutoWhiteBalance(val bool) error {
+	return w.setBoolControl(V4L2_CID_AUTO_WHITE_BALANCE, val)
+}
+
+// Gets automatic white balance correction
+func (w *Webcam) GetAutoWhiteBalance() (bool, error) {
+	return w.getBoolControl(V4L2_CID_AUTO_WHITE_BALANCE)
+}
+
+// Gets auto exposure mode
+func (w *Webcam) GetExposureMode() (ExposureMode, error) {
+	v, err := w.getNamedControl(V4L2_CID_EXPOSURE_AUTO)
+	return ExposureMode(v), err
+}
+
+// Sets auto exposure mode. Manual exposure time can be
+// set with SetExposure only in ExposureManual and
+// ExposureShutterPriority modes
+func (w *Webcam) SetExposureMode(mode ExposureMode) error {
+	return w.setNamedControl(V4L2_CID_EXPOSURE_AUTO, int32(mode))
+}
+
+// Gets absolute exposure time in 100 µs units
+func (w *Webcam) GetExposure() (int32, error) {
+	return w.getNamedControl(V4L2_CID_EXPOSURE_ABSOLUTE)
+}
+
+// Sets absolute exposure time in 100 µs units
+func (w *Webcam) SetExposure(value int32) error {
+	return w.setNamedControl(V4L2_CID_EXPOSURE_ABSOLUTE, value)
+}
+
+// Gets gain
+func (w *Webcam) GetGain() (int32, error) {
+	return w.getNamedControl(V4L2_CID_GAIN)
+}
+
+// Sets gain
+func (w *Webcam) SetGain(value int32) error {
+	return w.setNamedControl(V4L2_CID_GAIN, value)
+}
+
+// Gets brightness
+func (w *Webcam) GetBrightness() (int32, error) {
+	return w.getNamedControl(V4L2_CID_BRIGHTNESS)
+}
+
+// Sets brightness
+func (w *Webcam) SetBrightness(value int32) error {
+	return w.setNamedControl(V4L2_CID_BRIGHTNESS, value)
+}
+
+// Gets contrast
+func (w *Webcam) GetContrast() (int32, error) {
+	return w.getNamedControl(V4L2_CID_CONTRAST)
+}
+
+// Sets contrast
+func (w *Webcam) SetContrast(value int32) error {
+	return w.setNamedControl(V4L2_CID_CONTRAST, value)
+}
+
+// Gets saturation
+func (w *Webcam) GetSaturation() (int32, error) {
+	return w.getNamedControl(V4L2_CID_SATURATION)
+}
+
+// Sets saturation
+func (w *Webcam) SetSaturation(value int32) error {
+	return w.setNamedControl(V4L2_CID_SATURATION, value)
+}
+
+// Gets white balance temperature in Kelvin
+func (w *Webcam) GetWhiteBalanceTemperature() (int32, error) {
+	return w.getNamedControl(V4L2_CID_WHITE_BALANCE_TEMPERATURE)
+}
+
+// Sets white balance temperature in Kelvin.
+// Automatic white balance should be turned off first
+func (w *Webcam) SetWhiteBalanceTemperature(value int32) error {
+	return w.setNamedControl(V4L2_CID_WHITE_BALANCE_TEMPERATURE, value)
+}
+
+// Gets continuous automatic focus
+func (w *Webcam) GetAutoFocus() (bool, error) {
+	return w.getBoolControl(V4L2_CID_FOCUS_AUTO)
+}
+
+// Sets continuous automatic focus
+func (w *Webcam) SetAutoFocus(val bool) error {
+	return w.setBoolControl(V4L2_CID_FOCUS_AUTO, val)
+}
+
+// Gets absolute focus position
+func (w *Webcam) GetFocus() (int32, error) {
+	return w.getNamedControl(V4L2_CID_FOCUS_ABSOLUTE)
+}
+
+// Sets absolute focus position.
+// Automatic focus should be turned off first
+func (w *Webcam) SetFocus(value int32) error {
+	return w.setNamedControl(V4L2_CID_FOCUS_ABSOLUTE, value)
+}
+
+// Gets absolute zoom
+func (w *Webcam) GetZoom() (int32, error) {
+	return w.getNamedControl(V4L2_CID_ZOOM_ABSOLUTE)
+}
+
+// Sets absolute zoom
+func (w *Webcam) SetZoom(value int32) error {
+	return w.setNamedControl(V4L2_CID_ZOOM_ABSOLUTE, value)
+}
+
+// Gets absolute pan angle in arc seconds
+func (w *Webcam) GetPan() (int32, error) {
+	return w.getNamedControl(V4L2_CID_PAN_ABSOLUTE)
+}
+
+// Sets absolute pan angle in arc seconds
+func (w *Webcam) SetPan(value int32) error {
+	return w.setNamedControl(V4L2_CID_PAN_ABSOLUTE, value)
+}
+
+// Gets absolute tilt angle in arc seconds
+func (w *Webcam) GetTilt() (int32, error) {
+	return w.getNamedControl(V4L2_CID_TILT_ABSOLUTE)
+}
+
+// Sets absolute tilt angle in arc seconds
+func (w *Webcam) SetTilt(value int32) error {
+	return w.setNamedControl(V4L2_CID_TILT_ABSOLUTE, value)
+}
+
+// Gets power line frequency filter
+func (w *Webcam) GetPowerLineFrequency() (PowerLineFrequency, error) {
+	v, err := w.getNamedControl(V4L2_CID_POWER_LINE_FREQUENCY)
+	return PowerLineFrequency(v), err
+}
+
+// Sets power line frequency filter to avoid flicker
+func (w *Webcam) SetPowerLineFrequency(value PowerLineFrequency) error {
+	return w.setNamedControl(V4L2_CID_POWER_LINE_FREQUENCY, int32(value))
+}
+
+// Gets backlight compensation
+func (w *Webcam) GetBacklightCompensation() (int32, error) {
+	return w.getNamedControl(V4L2_CID_BACKLIGHT_COMPENSATION)
+}
+
+// Sets backlight compensation
+func (w *Webcam) SetBacklightCompensation(value int32) error {
+	return w.setNamedControl(V4L2_CID_BACKLIGHT_COMPENSATION, value)
+}
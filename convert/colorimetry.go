@@ -0,0 +1,170 @@
+package convert
+
+import (
+	"image"
+	"image/draw"
+
+	"github.com/blackjack/webcam"
+)
+
+// Matrix of YCbCr encoding, defined by luma weights of red and blue
+type Encoding int
+
+const (
+	EncodingBT601 Encoding = iota
+	EncodingBT709
+	EncodingBT2020
+	EncodingSMPTE240M
+)
+
+// Range of YCbCr samples
+type Range int
+
+const (
+	// Luma is 16 to 235, chroma is 16 to 240
+	RangeLimited Range = iota
+	// All samples use 0 to 255
+	RangeFull
+)
+
+// Parameters of YCbCr to RGB conversion
+type Colorimetry struct {
+	Encoding Encoding
+	Range    Range
+}
+
+// Returns colorimetry of YCbCr frames in given format.
+// Zero encoding and quantization are resolved to defaults of the
+// colorspace as in V4L2. To override values reported by a driver,
+// set YCbCrEncoding and Quantization of the format before the call
+func ColorimetryOf(f webcam.FrameFormat) Colorimetry {
+	var c Colorimetry
+
+	switch f.YCbCrEncoding {
+	case webcam.V4L2_YCBCR_ENC_709, webcam.V4L2_YCBCR_ENC_XV709:
+		c.Encoding = EncodingBT709
+	case webcam.V4L2_YCBCR_ENC_BT2020, webcam.V4L2_YCBCR_ENC_BT2020_CONST_LUM:
+		// Constant luminance is approximated by the usual matrix
+		c.Encoding = EncodingBT2020
+	case webcam.V4L2_YCBCR_ENC_SMPTE240M:
+		c.Encoding = EncodingSMPTE240M
+	case webcam.V4L2_YCBCR_ENC_DEFAULT:
+		switch f.Colorspace {
+		case webcam.V4L2_COLORSPACE_REC709, webcam.V4L2_COLORSPACE_DCI_P3:
+			c.Encoding = EncodingBT709
+		case webcam.V4L2_COLORSPACE_BT2020:
+			c.Encoding = EncodingBT2020
+		case webcam.V4L2_COLORSPACE_SMPTE240M:
+			c.Encoding = EncodingSMPTE240M
+		}
+	}
+
+	switch f.Quantization {
+	case webcam.V4L2_QUANTIZATION_FULL_RANGE:
+		c.Range = RangeFull
+	case webcam.V4L2_QUANTIZATION_DEFAULT:
+		if f.Colorspace == webcam.V4L2_COLORSPACE_JPEG &&
+			f.YCbCrEncoding != webcam.V4L2_YCBCR_ENC_XV601 && f.YCbCrEncoding != webcam.V4L2_YCBCR_ENC_XV709 {
+			c.Range = RangeFull
+		}
+	}
+	return c
+}
+
+// Converts frame to RGB image. YCbCr formats supported by ToImage are
+// converted with colorimetry of the format, see ColorimetryOf, other
+// formats are converted as by ToImage. MJPEG and JPEG frames are
+// always full range BT.601 as defined by JFIF
+func ToRGBA(frame []byte, f webcam.FrameFormat) (*image.RGBA, error) {
+	img, err := ToImage(frame, f)
+	if err != nil {
+		return nil, err
+	}
+
+	compressed := f.Format == webcam.V4L2_PIX_FMT_MJPEG || f.Format == webcam.V4L2_PIX_FMT_JPEG
+	if ycbcr, ok := img.(*image.YCbCr); ok && !compressed {
+		return YCbCrToRGBA(ycbcr, ColorimetryOf(f)), nil
+	}
+	if rgba, ok := img.(*image.RGBA); ok {
+		return rgba, nil
+	}
+
+	b := img.Bounds()
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	draw.Draw(rgba, rgba.Rect, img, b.Min, draw.Src)
+	return rgba, nil
+}
+
+// Converts YCbCr image to RGB with given colorimetry.
+// Unlike colors of image.YCbCr, which are always full range BT.601,
+// the result matches colors intended by the camera
+func YCbCrToRGBA(img *image.YCbCr, c Colorimetry) *image.RGBA {
+	m := newYCbCrMatrix(c)
+	b := img.Rect
+	rgba := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		pix := rgba.Pix[(y-b.Min.Y)*rgba.Stride:]
+		for x := b.Min.X; x < b.Max.X; x++ {
+			ci := img.COffset(x, y)
+			i := 4 * (x - b.Min.X)
+			pix[i], pix[i+1], pix[i+2] = m.rgb(img.Y[img.YOffset(x, y)], img.Cb[ci], img.Cr[ci])
+			pix[i+3] = 0xff
+		}
+	}
+	return rgba
+}
+
+// Fixed point coefficients of YCbCr to RGB conversion, scaled by 1<<16
+type ycbcrMatrix struct {
+	yOffset, y         int32
+	rCr, gCb, gCr, bCb int32
+}
+
+func newYCbCrMatrix(c Colorimetry) ycbcrMatrix {
+	var kr, kb float64
+	switch c.Encoding {
+	case EncodingBT709:
+		kr, kb = 0.2126, 0.0722
+	case EncodingBT2020:
+		kr, kb = 0.2627, 0.0593
+	case EncodingSMPTE240M:
+		kr, kb = 0.212, 0.087
+	default:
+		kr, kb = 0.299, 0.114
+	}
+	kg := 1 - kr - kb
+
+	yOffset, yScale, cScale := 0, 1.0, 1.0
+	if c.Range == RangeLimited {
+		yOffset, yScale, cScale = 16, 255.0/219, 255.0/224
+	}
+
+	fixed := func(v float64) int32 {
+		return int32(v*(1<<16) + 0.5)
+	}
+	return ycbcrMatrix{
+		yOffset: int32(yOffset),
+		y:       fixed(yScale),
+		rCr:     fixed(2 * (1 - kr) * cScale),
+		gCb:     fixed(2 * (1 - kb) * kb / kg * cScale),
+		gCr:     fixed(2 * (1 - kr) * kr / kg * cScale),
+		bCb:     fixed(2 * (1 - kb) * cScale),
+	}
+}
+
+func (m ycbcrMatrix) rgb(y, cb, cr uint8) (uint8, uint8, uint8) {
+	yy := (int32(y)-m.yOffset)*m.y + 1<<15
+	cb1, cr1 := int32(cb)-128, int32(cr)-128
+	return clamp8(yy + m.rCr*cr1), clamp8(yy - m.gCb*cb1 - m.gCr*cr1), clamp8(yy + m.bCb*cb1)
+}
+
+// Returns 8-bit value of a fixed point number scaled by 1<<16
+func clamp8(v int32) uint8 {
+	if v < 0 {
+		return 0
+	}
+	if v > 0xffffff {
+		return 0xff
+	}
+	return uint8(v >> 16)
+}
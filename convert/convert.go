@@ -0,0 +1,103 @@
+// Conversion of raw frames captured by webcam package
+// to images of Go image package.
+// Every function takes a frame, its size and stride, which is
+// the distance in bytes between the starts of two lines as reported
+// by V4L2 in bytesperline. Zero stride means lines are not padded.
+// Converted images don't reference the frame, so it can be released,
+// while views returned by View and *View functions read the frame
+// directly and are valid only until it is released
+package convert
+
+import (
+	"fmt"
+	"image"
+
+	"github.com/blackjack/webcam"
+)
+
+// Error returned for formats there is no conversion for
+type UnsupportedFormat struct {
+	Format webcam.PixelFormat
+}
+
+func (e *UnsupportedFormat) Error() string {
+	return fmt.Sprintf("Conversion of %s format is not supported", e.Format)
+}
+
+// Converts frame to an image according to given format.
+// Returns *image.YCbCr for YUV formats, *image.RGBA for RGB formats,
+// *image.Gray for GREY and *image.Gray16 for Y16 and other
+// greyscale formats with 10 to 14-bit samples, which are scaled to 16 bits,
+// and for Z16 depth.
+// MJPEG and JPEG frames are decoded with DecodeMJPEG.
+// Colors of *image.YCbCr are full range BT.601, ToRGBA converts
+// YCbCr frames with colorimetry reported by the driver instead
+func ToImage(frame []byte, f webcam.FrameFormat) (image.Image, error) {
+	width, height, stride := int(f.Width), int(f.Height), int(f.BytesPerLine)
+
+	switch f.Format {
+	case webcam.V4L2_PIX_FMT_YUYV:
+		return YUYV(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_UYVY:
+		return UYVY(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_NV12:
+		return NV12(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_NV21:
+		return NV21(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_YUV420:
+		return YU12(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_YVU420:
+		return YV12(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_RGB24:
+		return RGB24(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_BGR24:
+		return BGR24(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_RGB565:
+		return RGB565(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_GREY:
+		return Grey(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_Y16:
+		return Y16(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_Y10, webcam.V4L2_PIX_FMT_Y12, webcam.V4L2_PIX_FMT_Y14,
+		webcam.V4L2_PIX_FMT_Y10P, webcam.V4L2_PIX_FMT_Y10BPACK, webcam.V4L2_PIX_FMT_Y16_BE:
+		p, err := Unpack(frame, f)
+		if err != nil {
+			return nil, err
+		}
+		return p.Gray16(), nil
+	case webcam.V4L2_PIX_FMT_Z16:
+		return Z16(frame, width, height, stride, 1)
+	case webcam.V4L2_PIX_FMT_MJPEG, webcam.V4L2_PIX_FMT_JPEG:
+		return DecodeMJPEG(frame)
+	}
+	return nil, &UnsupportedFormat{f.Format}
+}
+
+// Returns stride to use for lines of given length
+// and checks that frame holds all of the lines
+func checkFrame(frame []byte, height, stride, lineLen int) (int, error) {
+	if stride == 0 {
+		stride = lineLen
+	}
+	if stride < lineLen {
+		return 0, fmt.Errorf("Stride %d is less than line length %d", stride, lineLen)
+	}
+	if height == 0 {
+		return stride, nil
+	}
+	return stride, checkSize(frame, stride*(height-1)+lineLen)
+}
+
+func checkSize(frame []byte, size int) error {
+	if len(frame) < size {
+		return fmt.Errorf("Frame is too short: %d bytes, expected %d", len(frame), size)
+	}
+	return nil
+}
+
+// Copies lines of given length between planes with different strides
+func copyPlane(dst []byte, dstStride int, src []byte, srcStride int, lineLen, lines int) {
+	for row := 0; row < lines; row++ {
+		copy(dst[row*dstStride:row*dstStride+lineLen], src[row*srcStride:row*srcStride+lineLen])
+	}
+}
@@ -0,0 +1,245 @@
+package convert
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"testing"
+
+	"github.com/blackjack/webcam"
+)
+
+// Luma of 4x2 YCbCr test frames
+var (
+	lumaTop    = []byte{50, 100, 150, 200}
+	lumaBottom = []byte{60, 110, 160, 210}
+)
+
+// Neutral chroma gives grey, the other chroma (Cb 90, Cr 200) gives
+// colors computed by color.YCbCrToRGB, which are full range BT.601.
+// Swapped chroma planes would give different colors
+func grey(v uint8) color.RGBA {
+	return color.RGBA{v, v, v, 0xff}
+}
+
+// 4:2:2 frames: neutral chroma for the left pair of the top line
+// and for the right pair of the bottom line
+var want422 = [][]color.RGBA{
+	{grey(50), grey(100), {251, 112, 83, 0xff}, {255, 162, 133, 0xff}},
+	{{161, 21, 0, 0xff}, {211, 72, 43, 0xff}, grey(160), grey(210)},
+}
+
+// 4:2:0 frames: neutral chroma for the left pairs of both lines
+var want420 = [][]color.RGBA{
+	{grey(50), grey(100), {251, 112, 83, 0xff}, {255, 162, 133, 0xff}},
+	{grey(60), grey(110), {255, 122, 93, 0xff}, {255, 172, 143, 0xff}},
+}
+
+// Returns lines joined with padding up to stride
+func frameOf(stride int, lines ...[]byte) []byte {
+	frame := make([]byte, 0)
+	for _, line := range lines {
+		frame = append(frame, line...)
+		for i := len(line); i < stride; i++ {
+			frame = append(frame, 0xee)
+		}
+	}
+	return frame
+}
+
+func join(parts ...[]byte) []byte {
+	return bytes.Join(parts, nil)
+}
+
+func checkImage(t *testing.T, img image.Image, want [][]color.RGBA) {
+	t.Helper()
+	b := img.Bounds()
+	if b.Dx() != len(want[0]) || b.Dy() != len(want) {
+		t.Fatalf("got size %dx%d, expected %dx%d", b.Dx(), b.Dy(), len(want[0]), len(want))
+	}
+	for y, line := range want {
+		for x, c := range line {
+			got := color.RGBAModel.Convert(img.At(b.Min.X+x, b.Min.Y+y)).(color.RGBA)
+			if got != c {
+				t.Errorf("pixel %d,%d is %v, expected %v", x, y, got, c)
+			}
+		}
+	}
+}
+
+func TestToImage(t *testing.T) {
+	tests := []struct {
+		name   string
+		format webcam.FrameFormat
+		frame  []byte
+		want   [][]color.RGBA
+	}{
+		{
+			"YUYV",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_YUYV, Width: 4, Height: 2},
+			frameOf(8,
+				[]byte{50, 128, 100, 128, 150, 90, 200, 200},
+				[]byte{60, 90, 110, 200, 160, 128, 210, 128}),
+			want422,
+		},
+		{
+			"YUYV padded",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_YUYV, Width: 4, Height: 2, BytesPerLine: 12},
+			frameOf(12,
+				[]byte{50, 128, 100, 128, 150, 90, 200, 200},
+				[]byte{60, 90, 110, 200, 160, 128, 210, 128}),
+			want422,
+		},
+		{
+			"UYVY",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_UYVY, Width: 4, Height: 2},
+			frameOf(8,
+				[]byte{128, 50, 128, 100, 90, 150, 200, 200},
+				[]byte{90, 60, 200, 110, 128, 160, 128, 210}),
+			want422,
+		},
+		{
+			"NV12",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_NV12, Width: 4, Height: 2},
+			frameOf(4, lumaTop, lumaBottom, []byte{128, 128, 90, 200}),
+			want420,
+		},
+		{
+			"NV12 padded",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_NV12, Width: 4, Height: 2, BytesPerLine: 6},
+			frameOf(6, lumaTop, lumaBottom, []byte{128, 128, 90, 200}),
+			want420,
+		},
+		{
+			"NV21",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_NV21, Width: 4, Height: 2},
+			frameOf(4, lumaTop, lumaBottom, []byte{128, 128, 200, 90}),
+			want420,
+		},
+		{
+			"YU12",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_YUV420, Width: 4, Height: 2},
+			join(lumaTop, lumaBottom, []byte{128, 90}, []byte{128, 200}),
+			want420,
+		},
+		{
+			"YU12 padded",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_YUV420, Width: 4, Height: 2, BytesPerLine: 6},
+			join(frameOf(6, lumaTop, lumaBottom), frameOf(3, []byte{128, 90}), frameOf(3, []byte{128, 200})),
+			want420,
+		},
+		{
+			"YV12",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_YVU420, Width: 4, Height: 2},
+			join(lumaTop, lumaBottom, []byte{128, 200}, []byte{128, 90}),
+			want420,
+		},
+		{
+			"RGB24",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_RGB24, Width: 2, Height: 2},
+			frameOf(6, []byte{255, 0, 0, 0, 255, 0}, []byte{0, 0, 255, 10, 20, 30}),
+			[][]color.RGBA{
+				{{255, 0, 0, 0xff}, {0, 255, 0, 0xff}},
+				{{0, 0, 255, 0xff}, {10, 20, 30, 0xff}},
+			},
+		},
+		{
+			"RGB24 padded",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_RGB24, Width: 2, Height: 2, BytesPerLine: 8},
+			frameOf(8, []byte{255, 0, 0, 0, 255, 0}, []byte{0, 0, 255, 10, 20, 30}),
+			[][]color.RGBA{
+				{{255, 0, 0, 0xff}, {0, 255, 0, 0xff}},
+				{{0, 0, 255, 0xff}, {10, 20, 30, 0xff}},
+			},
+		},
+		{
+			"BGR24",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_BGR24, Width: 2, Height: 1},
+			[]byte{0, 0, 255, 30, 20, 10},
+			[][]color.RGBA{{{255, 0, 0, 0xff}, {10, 20, 30, 0xff}}},
+		},
+		{
+			"RGB565",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_RGB565, Width: 4, Height: 1},
+			[]byte{0x00, 0xf8, 0xe0, 0x07, 0x1f, 0x00, 0x10, 0x84},
+			[][]color.RGBA{{{255, 0, 0, 0xff}, {0, 255, 0, 0xff}, {0, 0, 255, 0xff}, {132, 130, 132, 0xff}}},
+		},
+		{
+			"GREY padded",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_GREY, Width: 2, Height: 2, BytesPerLine: 4},
+			frameOf(4, []byte{0, 255}, []byte{17, 200}),
+			[][]color.RGBA{{grey(0), grey(255)}, {grey(17), grey(200)}},
+		},
+		{
+			"Y16",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_Y16, Width: 2, Height: 1},
+			[]byte{0x34, 0x12, 0xff, 0xff},
+			[][]color.RGBA{{grey(0x12), grey(0xff)}},
+		},
+		{
+			"Y10",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_Y10, Width: 2, Height: 1},
+			[]byte{0xff, 0x03, 0x00, 0x02},
+			[][]color.RGBA{{grey(0xff), grey(0x80)}},
+		},
+		{
+			"Z16",
+			webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_Z16, Width: 2, Height: 1},
+			[]byte{0x00, 0x00, 0x00, 0x40},
+			[][]color.RGBA{{grey(0), grey(0x40)}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			img, err := ToImage(test.frame, test.format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			checkImage(t, img, test.want)
+		})
+	}
+}
+
+func TestToImageMJPEG(t *testing.T) {
+	src := image.NewGray(image.Rect(0, 0, 16, 8))
+	for i := range src.Pix {
+		src.Pix[i] = 128
+	}
+	buf := &bytes.Buffer{}
+	if err := jpeg.Encode(buf, src, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := ToImage(buf.Bytes(), webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_MJPEG, Width: 16, Height: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := make([][]color.RGBA, 8)
+	for y := range want {
+		want[y] = make([]color.RGBA, 16)
+		for x := range want[y] {
+			want[y][x] = grey(128)
+		}
+	}
+	checkImage(t, img, want)
+}
+
+func TestToImageShortFrame(t *testing.T) {
+	f := webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_YUYV, Width: 4, Height: 2, BytesPerLine: 12}
+	// The last line doesn't need padding
+	if _, err := ToImage(make([]byte, 20), f); err != nil {
+		t.Errorf("frame without padding after the last line was rejected: %s", err)
+	}
+	if _, err := ToImage(make([]byte, 19), f); err == nil {
+		t.Error("short frame was accepted")
+	}
+}
+
+func TestToImageUnsupported(t *testing.T) {
+	_, err := ToImage(nil, webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_H264})
+	if _, ok := err.(*UnsupportedFormat); !ok {
+		t.Errorf("got error %v, expected UnsupportedFormat", err)
+	}
+}
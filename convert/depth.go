@@ -0,0 +1,133 @@
+package convert
+
+import (
+	"image"
+	"math"
+)
+
+// Converts Z16 depth frame to greyscale image.
+// Depth values are multiplied by scale and clamped to 16 bits,
+// e.g. scale 10 turns 1 mm units to 0.1 mm. Zero scale means 1.
+// Zero depth marks pixels without valid measurement and stays zero
+func Z16(frame []byte, width, height, stride int, scale float64) (*image.Gray16, error) {
+	p, err := unpackWords(frame, width, height, stride, 16, false)
+	if err != nil {
+		return nil, err
+	}
+	if scale == 0 || scale == 1 {
+		return p.Gray16(), nil
+	}
+
+	for i, v := range p.Pix {
+		p.Pix[i] = uint16(math.Min(float64(v)*scale+0.5, 0xffff))
+	}
+	return p.Gray16(), nil
+}
+
+// Splits Y8I frame of interleaved 8-bit samples of a stereo pair
+// to left and right images
+func Y8I(frame []byte, width, height, stride int) (left, right *image.Gray, err error) {
+	stride, err = checkFrame(frame, height, stride, 2*width)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	left = image.NewGray(image.Rect(0, 0, width, height))
+	right = image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		line := frame[y*stride:]
+		l, r := left.Pix[y*left.Stride:], right.Pix[y*right.Stride:]
+		for x := 0; x < width; x++ {
+			l[x], r[x] = line[2*x], line[2*x+1]
+		}
+	}
+	return left, right, nil
+}
+
+// Splits Y12I frame of interleaved 12-bit samples of a stereo pair
+// to left and right images, samples are scaled to 16 bits.
+// Every pixel is a 24-bit little-endian word with left sample
+// in bits 0-11 and right one in bits 12-23
+func Y12I(frame []byte, width, height, stride int) (left, right *image.Gray16, err error) {
+	stride, err = checkFrame(frame, height, stride, 3*width)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	l, r := newPlane16(width, height, 12), newPlane16(width, height, 12)
+	for y := 0; y < height; y++ {
+		line := frame[y*stride:]
+		lpix, rpix := l.Pix[y*l.Stride:], r.Pix[y*r.Stride:]
+		for x := 0; x < width; x++ {
+			b := line[3*x : 3*x+3]
+			lpix[x] = uint16(b[0]) | uint16(b[1]&0x0f)<<8
+			rpix[x] = uint16(b[1]>>4) | uint16(b[2])<<4
+		}
+	}
+	return l.Gray16(), r.Gray16(), nil
+}
+
+// Returns depth image colored from blue for near to red for far
+// pixels, pixels with zero depth are black. Depths outside of
+// near to far range are clamped, if far is not greater than near,
+// the range of valid depths in the image is used
+func FalseColor(depth *image.Gray16, near, far uint16) *image.RGBA {
+	b := depth.Bounds()
+	if far <= near {
+		near, far = depthRange(depth)
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		pix := img.Pix[(y-b.Min.Y)*img.Stride:]
+		for x := b.Min.X; x < b.Max.X; x++ {
+			v := depth.Gray16At(x, y).Y
+			i := 4 * (x - b.Min.X)
+			pix[i+3] = 0xff
+			if v == 0 {
+				continue
+			}
+
+			t := 0.0
+			if far > near {
+				t = (float64(v) - float64(near)) / float64(far-near)
+			}
+			pix[i], pix[i+1], pix[i+2] = jet(t)
+		}
+	}
+	return img
+}
+
+// Returns the smallest and the largest non-zero sample
+func depthRange(depth *image.Gray16) (min, max uint16) {
+	b := depth.Bounds()
+	min = 0xffff
+	for y := b.Min.Y; y < b.Max.Y; y++ {
+		for x := b.Min.X; x < b.Max.X; x++ {
+			v := depth.Gray16At(x, y).Y
+			if v == 0 {
+				continue
+			}
+			if v < min {
+				min = v
+			}
+			if v > max {
+				max = v
+			}
+		}
+	}
+	if max == 0 {
+		return 0, 0
+	}
+	return min, max
+}
+
+// Maps value from 0 to 1 to blue, cyan, green, yellow and red
+func jet(t float64) (r, g, b uint8) {
+	channel := func(center float64) uint8 {
+		v := 1.5 - math.Abs(4*t-center)
+		return uint8(255*math.Max(0, math.Min(1, v)) + 0.5)
+	}
+	t = math.Max(0, math.Min(1, t))
+	return channel(3), channel(2), channel(1)
+}
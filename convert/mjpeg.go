@@ -0,0 +1,211 @@
+package convert
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/jpeg"
+)
+
+// JPEG markers
+const (
+	markerSOI  = 0xd8
+	markerEOI  = 0xd9
+	markerSOS  = 0xda
+	markerDHT  = 0xc4
+	markerTEM  = 0x01
+	markerRST0 = 0xd0
+	markerRST7 = 0xd7
+)
+
+// Repairs an MJPEG frame so that it can be decoded by image/jpeg:
+// checks SOI and EOI markers, removes data following EOI and inserts
+// standard Huffman tables if the frame has none, which is common
+// for UVC cameras.
+// Returned slice shares memory with the frame unless tables were inserted
+func FixMJPEG(frame []byte) ([]byte, error) {
+	if len(frame) < 2 || frame[0] != 0xff || frame[1] != markerSOI {
+		return nil, errors.New("Invalid MJPEG frame: missing SOI marker")
+	}
+
+	hasDHT := false
+	insertAt := -1
+	pos := 2
+	for {
+		// Markers may be preceded by fill bytes
+		for pos+1 < len(frame) && frame[pos] == 0xff && frame[pos+1] == 0xff {
+			pos++
+		}
+		if pos+1 >= len(frame) {
+			return nil, errors.New("Invalid MJPEG frame: missing EOI marker")
+		}
+		if frame[pos] != 0xff {
+			return nil, errors.New("Invalid MJPEG frame: marker expected")
+		}
+
+		marker := frame[pos+1]
+		if marker == markerEOI {
+			pos += 2
+			break
+		}
+		if marker == markerTEM || (marker >= markerRST0 && marker <= markerRST7) {
+			pos += 2
+			continue
+		}
+
+		if pos+4 > len(frame) {
+			return nil, errors.New("Invalid MJPEG frame: truncated segment")
+		}
+		length := int(binary.BigEndian.Uint16(frame[pos+2:]))
+		if length < 2 || pos+2+length > len(frame) {
+			return nil, errors.New("Invalid MJPEG frame: truncated segment")
+		}
+
+		switch marker {
+		case markerDHT:
+			hasDHT = true
+		case markerSOS:
+			if !hasDHT && insertAt < 0 {
+				insertAt = pos
+			}
+		}
+		pos += 2 + length
+
+		if marker == markerSOS {
+			pos = skipEntropyData(frame, pos)
+		}
+	}
+
+	frame = frame[:pos]
+	if insertAt < 0 {
+		return frame, nil
+	}
+
+	fixed := make([]byte, 0, len(frame)+len(standardDHT))
+	fixed = append(fixed, frame[:insertAt]...)
+	fixed = append(fixed, standardDHT...)
+	fixed = append(fixed, frame[insertAt:]...)
+	return fixed, nil
+}
+
+// Repairs an MJPEG frame with FixMJPEG and decodes it
+func DecodeMJPEG(frame []byte) (image.Image, error) {
+	fixed, err := FixMJPEG(frame)
+	if err != nil {
+		return nil, err
+	}
+	return jpeg.Decode(bytes.NewReader(fixed))
+}
+
+// Returns position of the marker ending entropy-coded data.
+// Zero-stuffed 0xff bytes and restart markers belong to the data
+func skipEntropyData(frame []byte, pos int) int {
+	for ; pos+1 < len(frame); pos++ {
+		if frame[pos] != 0xff {
+			continue
+		}
+		next := frame[pos+1]
+		if next != 0 && next != 0xff && (next < markerRST0 || next > markerRST7) {
+			return pos
+		}
+	}
+	return len(frame)
+}
+
+type huffmanTable struct {
+	// Table class in high 4 bits (0 for DC, 1 for AC) and destination in low ones
+	class  byte
+	counts [16]byte
+	values []byte
+}
+
+// Tables from section K.3 of the JPEG specification, which MJPEG
+// streams without DHT segment are encoded with
+var standardHuffmanTables = []huffmanTable{
+	// Luminance DC
+	{
+		0x00,
+		[16]byte{0, 1, 5, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0, 0, 0},
+		[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+	},
+	// Luminance AC
+	{
+		0x10,
+		[16]byte{0, 2, 1, 3, 3, 2, 4, 3, 5, 5, 4, 4, 0, 0, 1, 125},
+		[]byte{
+			0x01, 0x02, 0x03, 0x00, 0x04, 0x11, 0x05, 0x12,
+			0x21, 0x31, 0x41, 0x06, 0x13, 0x51, 0x61, 0x07,
+			0x22, 0x71, 0x14, 0x32, 0x81, 0x91, 0xa1, 0x08,
+			0x23, 0x42, 0xb1, 0xc1, 0x15, 0x52, 0xd1, 0xf0,
+			0x24, 0x33, 0x62, 0x72, 0x82, 0x09, 0x0a, 0x16,
+			0x17, 0x18, 0x19, 0x1a, 0x25, 0x26, 0x27, 0x28,
+			0x29, 0x2a, 0x34, 0x35, 0x36, 0x37, 0x38, 0x39,
+			0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48, 0x49,
+			0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58, 0x59,
+			0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69,
+			0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78, 0x79,
+			0x7a, 0x83, 0x84, 0x85, 0x86, 0x87, 0x88, 0x89,
+			0x8a, 0x92, 0x93, 0x94, 0x95, 0x96, 0x97, 0x98,
+			0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5, 0xa6, 0xa7,
+			0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4, 0xb5, 0xb6,
+			0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3, 0xc4, 0xc5,
+			0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2, 0xd3, 0xd4,
+			0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda, 0xe1, 0xe2,
+			0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9, 0xea,
+			0xf1, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+			0xf9, 0xfa,
+		},
+	},
+	// Chrominance DC
+	{
+		0x01,
+		[16]byte{0, 3, 1, 1, 1, 1, 1, 1, 1, 1, 1, 0, 0, 0, 0, 0},
+		[]byte{0, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11},
+	},
+	// Chrominance AC
+	{
+		0x11,
+		[16]byte{0, 2, 1, 2, 4, 4, 3, 4, 7, 5, 4, 4, 0, 1, 2, 119},
+		[]byte{
+			0x00, 0x01, 0x02, 0x03, 0x11, 0x04, 0x05, 0x21,
+			0x31, 0x06, 0x12, 0x41, 0x51, 0x07, 0x61, 0x71,
+			0x13, 0x22, 0x32, 0x81, 0x08, 0x14, 0x42, 0x91,
+			0xa1, 0xb1, 0xc1, 0x09, 0x23, 0x33, 0x52, 0xf0,
+			0x15, 0x62, 0x72, 0xd1, 0x0a, 0x16, 0x24, 0x34,
+			0xe1, 0x25, 0xf1, 0x17, 0x18, 0x19, 0x1a, 0x26,
+			0x27, 0x28, 0x29, 0x2a, 0x35, 0x36, 0x37, 0x38,
+			0x39, 0x3a, 0x43, 0x44, 0x45, 0x46, 0x47, 0x48,
+			0x49, 0x4a, 0x53, 0x54, 0x55, 0x56, 0x57, 0x58,
+			0x59, 0x5a, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68,
+			0x69, 0x6a, 0x73, 0x74, 0x75, 0x76, 0x77, 0x78,
+			0x79, 0x7a, 0x82, 0x83, 0x84, 0x85, 0x86, 0x87,
+			0x88, 0x89, 0x8a, 0x92, 0x93, 0x94, 0x95, 0x96,
+			0x97, 0x98, 0x99, 0x9a, 0xa2, 0xa3, 0xa4, 0xa5,
+			0xa6, 0xa7, 0xa8, 0xa9, 0xaa, 0xb2, 0xb3, 0xb4,
+			0xb5, 0xb6, 0xb7, 0xb8, 0xb9, 0xba, 0xc2, 0xc3,
+			0xc4, 0xc5, 0xc6, 0xc7, 0xc8, 0xc9, 0xca, 0xd2,
+			0xd3, 0xd4, 0xd5, 0xd6, 0xd7, 0xd8, 0xd9, 0xda,
+			0xe2, 0xe3, 0xe4, 0xe5, 0xe6, 0xe7, 0xe8, 0xe9,
+			0xea, 0xf2, 0xf3, 0xf4, 0xf5, 0xf6, 0xf7, 0xf8,
+			0xf9, 0xfa,
+		},
+	},
+}
+
+var standardDHT = dhtSegment(standardHuffmanTables)
+
+func dhtSegment(tables []huffmanTable) []byte {
+	length := 2
+	for _, t := range tables {
+		length += 1 + len(t.counts) + len(t.values)
+	}
+
+	segment := []byte{0xff, markerDHT, byte(length >> 8), byte(length)}
+	for _, t := range tables {
+		segment = append(segment, t.class)
+		segment = append(segment, t.counts[:]...)
+		segment = append(segment, t.values...)
+	}
+	return segment
+}
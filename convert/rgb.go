@@ -0,0 +1,96 @@
+package convert
+
+import (
+	"encoding/binary"
+	"image"
+)
+
+// Converts packed frame with R G B byte order
+func RGB24(frame []byte, width, height, stride int) (*image.RGBA, error) {
+	return packed24(frame, width, height, stride, 0, 2)
+}
+
+// Converts packed frame with B G R byte order
+func BGR24(frame []byte, width, height, stride int) (*image.RGBA, error) {
+	return packed24(frame, width, height, stride, 2, 0)
+}
+
+// Converts frame with little-endian 16-bit pixels,
+// 5 bits of red in the high bits, 6 bits of green and 5 bits of blue
+func RGB565(frame []byte, width, height, stride int) (*image.RGBA, error) {
+	stride, err := checkFrame(frame, height, stride, 2*width)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for row := 0; row < height; row++ {
+		line := frame[row*stride:]
+		pix := img.Pix[row*img.Stride:]
+		for x := 0; x < width; x++ {
+			v := binary.LittleEndian.Uint16(line[2*x:])
+			r, g, b := byte(v>>11), byte(v>>5)&0x3f, byte(v)&0x1f
+			// High bits are repeated in low ones, so that
+			// the maximum value maps to 255
+			pix[4*x] = r<<3 | r>>2
+			pix[4*x+1] = g<<2 | g>>4
+			pix[4*x+2] = b<<3 | b>>2
+			pix[4*x+3] = 0xff
+		}
+	}
+	return img, nil
+}
+
+// Converts 8-bit greyscale frame
+func Grey(frame []byte, width, height, stride int) (*image.Gray, error) {
+	stride, err := checkFrame(frame, height, stride, width)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	copyPlane(img.Pix, img.Stride, frame, stride, width, height)
+	return img, nil
+}
+
+// Converts 16-bit little-endian greyscale frame
+func Y16(frame []byte, width, height, stride int) (*image.Gray16, error) {
+	stride, err := checkFrame(frame, height, stride, 2*width)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewGray16(image.Rect(0, 0, width, height))
+	for row := 0; row < height; row++ {
+		line := frame[row*stride:]
+		pix := img.Pix[row*img.Stride:]
+		for x := 0; x < width; x++ {
+			// image.Gray16 is big-endian
+			pix[2*x] = line[2*x+1]
+			pix[2*x+1] = line[2*x]
+		}
+	}
+	return img, nil
+}
+
+// Offsets are positions of red and blue within 3 byte pixels
+func packed24(frame []byte, width, height, stride int, r, b int) (*image.RGBA, error) {
+	stride, err := checkFrame(frame, height, stride, 3*width)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for row := 0; row < height; row++ {
+		line := frame[row*stride:]
+		pix := img.Pix[row*img.Stride:]
+		for x := 0; x < width; x++ {
+			pixel := line[3*x : 3*x+3]
+			pix[4*x] = pixel[r]
+			pix[4*x+1] = pixel[1]
+			pix[4*x+2] = pixel[b]
+			pix[4*x+3] = 0xff
+		}
+	}
+	return img, nil
+}
@@ -0,0 +1,208 @@
+package convert
+
+import (
+	"image"
+
+	"github.com/blackjack/webcam"
+)
+
+// Samples of a greyscale or raw Bayer frame unpacked to 16-bit values.
+// Samples keep their original depth, e.g. Y10 values are 0 to 1023
+type Plane16 struct {
+	Pix []uint16
+	// Distance between lines in samples
+	Stride int
+	Width  int
+	Height int
+	// Number of significant bits of samples
+	Depth int
+}
+
+// Unpacks samples of a frame in greyscale or raw Bayer format.
+// Supported layouts are 8-bit samples (GREY, SxxxxX8), 10 to 16-bit samples
+// in little-endian words (Y10, Y12, Y14, Y16, SxxxxX10 to SxxxxX16),
+// big-endian words (Y16_BE), MIPI packed samples (Y10P, SxxxxX10P,
+// SxxxxX12P, SxxxxX14P) and big-endian bitstream (Y10BPACK)
+func Unpack(frame []byte, f webcam.FrameFormat) (*Plane16, error) {
+	width, height, stride := int(f.Width), int(f.Height), int(f.BytesPerLine)
+
+	switch f.Format {
+	case webcam.V4L2_PIX_FMT_Y10P, webcam.V4L2_PIX_FMT_SBGGR10P, webcam.V4L2_PIX_FMT_SGBRG10P,
+		webcam.V4L2_PIX_FMT_SGRBG10P, webcam.V4L2_PIX_FMT_SRGGB10P:
+		return UnpackMIPI10(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_SBGGR12P, webcam.V4L2_PIX_FMT_SGBRG12P,
+		webcam.V4L2_PIX_FMT_SGRBG12P, webcam.V4L2_PIX_FMT_SRGGB12P:
+		return UnpackMIPI12(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_SBGGR14P, webcam.V4L2_PIX_FMT_SGBRG14P,
+		webcam.V4L2_PIX_FMT_SGRBG14P, webcam.V4L2_PIX_FMT_SRGGB14P:
+		return UnpackMIPI14(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_Y10BPACK:
+		return UnpackY10BPACK(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_Y16_BE:
+		return unpackWords(frame, width, height, stride, 16, true)
+	}
+
+	info, ok := f.Format.Info()
+	if !ok || info.Compressed || info.Planes != 1 || info.Subsampling != webcam.SubsamplingNone {
+		return nil, &UnsupportedFormat{f.Format}
+	}
+	grey := f.Format == webcam.V4L2_PIX_FMT_GREY || f.Format == webcam.V4L2_PIX_FMT_Y10 ||
+		f.Format == webcam.V4L2_PIX_FMT_Y12 || f.Format == webcam.V4L2_PIX_FMT_Y14 ||
+		f.Format == webcam.V4L2_PIX_FMT_Y16
+	if !grey && info.Bayer == webcam.BayerNone {
+		return nil, &UnsupportedFormat{f.Format}
+	}
+
+	switch {
+	case info.BitsPerPixel == 8 && info.Depth == 8:
+		return Unpack8(frame, width, height, stride)
+	case info.BitsPerPixel == 16:
+		return UnpackLE16(frame, width, height, stride, info.Depth)
+	}
+	return nil, &UnsupportedFormat{f.Format}
+}
+
+// Unpacks 8-bit samples
+func Unpack8(frame []byte, width, height, stride int) (*Plane16, error) {
+	stride, err := checkFrame(frame, height, stride, width)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newPlane16(width, height, 8)
+	for y := 0; y < height; y++ {
+		line := frame[y*stride:]
+		pix := p.Pix[y*p.Stride:]
+		for x := 0; x < width; x++ {
+			pix[x] = uint16(line[x])
+		}
+	}
+	return p, nil
+}
+
+// Unpacks samples of given depth stored in low bits
+// of 16-bit little-endian words
+func UnpackLE16(frame []byte, width, height, stride, depth int) (*Plane16, error) {
+	return unpackWords(frame, width, height, stride, depth, false)
+}
+
+// Unpacks MIPI RAW10 layout: 4 samples in 5 bytes, high 8 bits
+// of every sample first, then a byte with low 2 bits of all of them
+func UnpackMIPI10(frame []byte, width, height, stride int) (*Plane16, error) {
+	return unpackGroups(frame, width, height, stride, 10, 4, 5, func(g []byte, s []uint16) {
+		for i := 0; i < 4; i++ {
+			s[i] = uint16(g[i])<<2 | uint16(g[4]>>uint(2*i))&0x03
+		}
+	})
+}
+
+// Unpacks MIPI RAW12 layout: 2 samples in 3 bytes, high 8 bits
+// of both samples first, then a byte with low 4 bits of them
+func UnpackMIPI12(frame []byte, width, height, stride int) (*Plane16, error) {
+	return unpackGroups(frame, width, height, stride, 12, 2, 3, func(g []byte, s []uint16) {
+		s[0] = uint16(g[0])<<4 | uint16(g[2])&0x0f
+		s[1] = uint16(g[1])<<4 | uint16(g[2]>>4)
+	})
+}
+
+// Unpacks MIPI RAW14 layout: 4 samples in 7 bytes, high 8 bits
+// of every sample first, then 3 bytes with low 6 bits of them
+func UnpackMIPI14(frame []byte, width, height, stride int) (*Plane16, error) {
+	return unpackGroups(frame, width, height, stride, 14, 4, 7, func(g []byte, s []uint16) {
+		low := uint32(g[4]) | uint32(g[5])<<8 | uint32(g[6])<<16
+		for i := 0; i < 4; i++ {
+			s[i] = uint16(g[i])<<6 | uint16(low>>uint(6*i))&0x3f
+		}
+	})
+}
+
+// Unpacks 10-bit samples packed to a big-endian bitstream,
+// 4 samples in 5 bytes
+func UnpackY10BPACK(frame []byte, width, height, stride int) (*Plane16, error) {
+	stride, err := checkFrame(frame, height, stride, (width*10+7)/8)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newPlane16(width, height, 10)
+	for y := 0; y < height; y++ {
+		line := frame[y*stride:]
+		pix := p.Pix[y*p.Stride:]
+		for x := 0; x < width; x++ {
+			bit := x * 10
+			// Sample never spans more than 2 bytes, as it starts at even bit
+			v := uint16(line[bit/8])<<8 | uint16(line[bit/8+1])
+			pix[x] = v >> uint(6-bit%8) & 0x3ff
+		}
+	}
+	return p, nil
+}
+
+// Returns greyscale image with samples scaled to 16 bits
+func (p *Plane16) Gray16() *image.Gray16 {
+	img := image.NewGray16(image.Rect(0, 0, p.Width, p.Height))
+	shift := uint(16 - p.Depth)
+	for y := 0; y < p.Height; y++ {
+		line := p.Pix[y*p.Stride:]
+		pix := img.Pix[y*img.Stride:]
+		for x := 0; x < p.Width; x++ {
+			v := line[x]
+			if shift > 0 && shift < 16 {
+				// High bits are repeated in low ones, so that
+				// the maximum value maps to 65535
+				v = v<<shift | v>>uint(p.Depth-int(shift))
+			}
+			pix[2*x] = byte(v >> 8)
+			pix[2*x+1] = byte(v)
+		}
+	}
+	return img
+}
+
+func newPlane16(width, height, depth int) *Plane16 {
+	return &Plane16{make([]uint16, width*height), width, width, height, depth}
+}
+
+func unpackWords(frame []byte, width, height, stride, depth int, bigEndian bool) (*Plane16, error) {
+	stride, err := checkFrame(frame, height, stride, 2*width)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newPlane16(width, height, depth)
+	mask := uint16(1<<uint(depth) - 1)
+	for y := 0; y < height; y++ {
+		line := frame[y*stride:]
+		pix := p.Pix[y*p.Stride:]
+		for x := 0; x < width; x++ {
+			if bigEndian {
+				pix[x] = (uint16(line[2*x])<<8 | uint16(line[2*x+1])) & mask
+			} else {
+				pix[x] = (uint16(line[2*x]) | uint16(line[2*x+1])<<8) & mask
+			}
+		}
+	}
+	return p, nil
+}
+
+// Unpacks lines made of groups of given number of samples
+// stored in given number of bytes
+func unpackGroups(frame []byte, width, height, stride, depth, samples, size int, unpack func(group []byte, s []uint16)) (*Plane16, error) {
+	groups := (width + samples - 1) / samples
+	stride, err := checkFrame(frame, height, stride, groups*size)
+	if err != nil {
+		return nil, err
+	}
+
+	p := newPlane16(width, height, depth)
+	s := make([]uint16, samples)
+	for y := 0; y < height; y++ {
+		line := frame[y*stride:]
+		pix := p.Pix[y*p.Stride : (y+1)*p.Stride]
+		for g := 0; g < groups; g++ {
+			unpack(line[g*size:g*size+size], s)
+			copy(pix[g*samples:], s)
+		}
+	}
+	return p, nil
+}
@@ -0,0 +1,215 @@
+package convert
+
+import (
+	"image"
+	"image/color"
+
+	"github.com/blackjack/webcam"
+)
+
+// Returns an image reading pixels directly from the frame without copying it.
+// Supported formats are YUYV, UYVY, RGB24, BGR24, GREY and Y16.
+// The image is valid only until the frame is released with ReleaseFrame,
+// after that the driver reuses the buffer for new frames
+func View(frame []byte, f webcam.FrameFormat) (image.Image, error) {
+	width, height, stride := int(f.Width), int(f.Height), int(f.BytesPerLine)
+
+	switch f.Format {
+	case webcam.V4L2_PIX_FMT_YUYV:
+		return YUYVView(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_UYVY:
+		return UYVYView(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_RGB24:
+		return RGB24View(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_BGR24:
+		return BGR24View(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_GREY:
+		return GreyView(frame, width, height, stride)
+	case webcam.V4L2_PIX_FMT_Y16:
+		return Y16View(frame, width, height, stride)
+	}
+	return nil, &UnsupportedFormat{f.Format}
+}
+
+// Packed 4:2:2 image backed by frame memory.
+// Two horizontally adjacent pixels share chroma samples,
+// so the image is read-only
+type PackedYUV struct {
+	Pix    []byte
+	Stride int
+	Rect   image.Rectangle
+
+	// Positions of the first luma sample and chroma samples
+	// within 4 byte group of two pixels
+	y, cb, cr int
+}
+
+// Returns view of a frame with Y0 Cb Y1 Cr byte order
+func YUYVView(frame []byte, width, height, stride int) (*PackedYUV, error) {
+	return packedYUVView(frame, width, height, stride, 0, 1, 3)
+}
+
+// Returns view of a frame with Cb Y0 Cr Y1 byte order
+func UYVYView(frame []byte, width, height, stride int) (*PackedYUV, error) {
+	return packedYUVView(frame, width, height, stride, 1, 0, 2)
+}
+
+func packedYUVView(frame []byte, width, height, stride int, y, cb, cr int) (*PackedYUV, error) {
+	stride, err := checkFrame(frame, height, stride, 4*((width+1)/2))
+	if err != nil {
+		return nil, err
+	}
+	return &PackedYUV{frame, stride, image.Rect(0, 0, width, height), y, cb, cr}, nil
+}
+
+func (p *PackedYUV) ColorModel() color.Model {
+	return color.YCbCrModel
+}
+
+func (p *PackedYUV) Bounds() image.Rectangle {
+	return p.Rect
+}
+
+func (p *PackedYUV) At(x, y int) color.Color {
+	return p.YCbCrAt(x, y)
+}
+
+func (p *PackedYUV) YCbCrAt(x, y int) color.YCbCr {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return color.YCbCr{}
+	}
+	x, y = x-p.Rect.Min.X, y-p.Rect.Min.Y
+	group := p.Pix[y*p.Stride+(x/2)*4:]
+	return color.YCbCr{group[p.y+(x%2)*2], group[p.cb], group[p.cr]}
+}
+
+// Packed 24-bit RGB image backed by frame memory
+type PackedRGB struct {
+	Pix    []byte
+	Stride int
+	Rect   image.Rectangle
+
+	// Positions of red and blue within 3 byte pixels
+	r, b int
+}
+
+// Returns view of a frame with R G B byte order
+func RGB24View(frame []byte, width, height, stride int) (*PackedRGB, error) {
+	return packedRGBView(frame, width, height, stride, 0, 2)
+}
+
+// Returns view of a frame with B G R byte order
+func BGR24View(frame []byte, width, height, stride int) (*PackedRGB, error) {
+	return packedRGBView(frame, width, height, stride, 2, 0)
+}
+
+func packedRGBView(frame []byte, width, height, stride int, r, b int) (*PackedRGB, error) {
+	stride, err := checkFrame(frame, height, stride, 3*width)
+	if err != nil {
+		return nil, err
+	}
+	return &PackedRGB{frame, stride, image.Rect(0, 0, width, height), r, b}, nil
+}
+
+func (p *PackedRGB) ColorModel() color.Model {
+	return color.RGBAModel
+}
+
+func (p *PackedRGB) Bounds() image.Rectangle {
+	return p.Rect
+}
+
+func (p *PackedRGB) Opaque() bool {
+	return true
+}
+
+func (p *PackedRGB) At(x, y int) color.Color {
+	return p.RGBAAt(x, y)
+}
+
+func (p *PackedRGB) RGBAAt(x, y int) color.RGBA {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return color.RGBA{}
+	}
+	pixel := p.Pix[p.offset(x, y):]
+	return color.RGBA{pixel[p.r], pixel[1], pixel[p.b], 0xff}
+}
+
+// Writes the color to the frame, alpha is ignored
+func (p *PackedRGB) Set(x, y int, c color.Color) {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return
+	}
+	rgba := color.RGBAModel.Convert(c).(color.RGBA)
+	pixel := p.Pix[p.offset(x, y):]
+	pixel[p.r], pixel[1], pixel[p.b] = rgba.R, rgba.G, rgba.B
+}
+
+func (p *PackedRGB) offset(x, y int) int {
+	return (y-p.Rect.Min.Y)*p.Stride + (x-p.Rect.Min.X)*3
+}
+
+// Returns view of 8-bit greyscale frame.
+// Layout of the frame matches image.Gray, so it is used directly
+func GreyView(frame []byte, width, height, stride int) (*image.Gray, error) {
+	stride, err := checkFrame(frame, height, stride, width)
+	if err != nil {
+		return nil, err
+	}
+	return &image.Gray{Pix: frame, Stride: stride, Rect: image.Rect(0, 0, width, height)}, nil
+}
+
+// 16-bit little-endian greyscale image backed by frame memory.
+// Unlike image.Gray16, samples are stored little-endian as
+// delivered by V4L2
+type Gray16LE struct {
+	Pix    []byte
+	Stride int
+	Rect   image.Rectangle
+}
+
+// Returns view of 16-bit little-endian greyscale frame
+func Y16View(frame []byte, width, height, stride int) (*Gray16LE, error) {
+	stride, err := checkFrame(frame, height, stride, 2*width)
+	if err != nil {
+		return nil, err
+	}
+	return &Gray16LE{frame, stride, image.Rect(0, 0, width, height)}, nil
+}
+
+func (p *Gray16LE) ColorModel() color.Model {
+	return color.Gray16Model
+}
+
+func (p *Gray16LE) Bounds() image.Rectangle {
+	return p.Rect
+}
+
+func (p *Gray16LE) Opaque() bool {
+	return true
+}
+
+func (p *Gray16LE) At(x, y int) color.Color {
+	return p.Gray16At(x, y)
+}
+
+func (p *Gray16LE) Gray16At(x, y int) color.Gray16 {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return color.Gray16{}
+	}
+	i := p.offset(x, y)
+	return color.Gray16{uint16(p.Pix[i]) | uint16(p.Pix[i+1])<<8}
+}
+
+func (p *Gray16LE) Set(x, y int, c color.Color) {
+	if !(image.Point{x, y}.In(p.Rect)) {
+		return
+	}
+	v := color.Gray16Model.Convert(c).(color.Gray16).Y
+	i := p.offset(x, y)
+	p.Pix[i], p.Pix[i+1] = byte(v), byte(v>>8)
+}
+
+func (p *Gray16LE) offset(x, y int) int {
+	return (y-p.Rect.Min.Y)*p.Stride + (x-p.Rect.Min.X)*2
+}
@@ -0,0 +1,117 @@
+package convert
+
+import "image"
+
+// Converts packed 4:2:2 frame with Y0 Cb Y1 Cr byte order
+func YUYV(frame []byte, width, height, stride int) (*image.YCbCr, error) {
+	return packed422(frame, width, height, stride, 0, 1, 3)
+}
+
+// Converts packed 4:2:2 frame with Cb Y0 Cr Y1 byte order
+func UYVY(frame []byte, width, height, stride int) (*image.YCbCr, error) {
+	return packed422(frame, width, height, stride, 1, 0, 2)
+}
+
+// Converts 4:2:0 frame with Y plane followed by interleaved Cb Cr plane
+func NV12(frame []byte, width, height, stride int) (*image.YCbCr, error) {
+	return semiPlanar420(frame, width, height, stride, 0, 1)
+}
+
+// Converts 4:2:0 frame with Y plane followed by interleaved Cr Cb plane
+func NV21(frame []byte, width, height, stride int) (*image.YCbCr, error) {
+	return semiPlanar420(frame, width, height, stride, 1, 0)
+}
+
+// Converts 4:2:0 frame with Y, Cb and Cr planes (V4L2_PIX_FMT_YUV420).
+// Chroma planes have half of the stride of Y plane
+func YU12(frame []byte, width, height, stride int) (*image.YCbCr, error) {
+	return planar420(frame, width, height, stride, false)
+}
+
+// Converts 4:2:0 frame with Y, Cr and Cb planes (V4L2_PIX_FMT_YVU420).
+// Chroma planes have half of the stride of Y plane
+func YV12(frame []byte, width, height, stride int) (*image.YCbCr, error) {
+	return planar420(frame, width, height, stride, true)
+}
+
+// Offsets are positions of the first luma sample and chroma
+// samples within 4 byte group of two pixels
+func packed422(frame []byte, width, height, stride int, y, cb, cr int) (*image.YCbCr, error) {
+	cw := (width + 1) / 2
+	stride, err := checkFrame(frame, height, stride, 4*cw)
+	if err != nil {
+		return nil, err
+	}
+
+	img := image.NewYCbCr(image.Rect(0, 0, width, height), image.YCbCrSubsampleRatio422)
+	for row := 0; row < height; row++ {
+		line := frame[row*stride:]
+		yLine := img.Y[row*img.YStride:]
+		cbLine := img.Cb[row*img.CStride:]
+		crLine := img.Cr[row*img.CStride:]
+		for x := 0; x < cw; x++ {
+			group := line[4*x : 4*x+4]
+			yLine[2*x] = group[y]
+			if 2*x+1 < width {
+				yLine[2*x+1] = group[y+2]
+			}
+			cbLine[x] = group[cb]
+			crLine[x] = group[cr]
+		}
+	}
+	return img, nil
+}
+
+// Offsets are positions of Cb and Cr within interleaved pairs
+func semiPlanar420(frame []byte, width, height, stride int, cb, cr int) (*image.YCbCr, error) {
+	cw, ch := (width+1)/2, (height+1)/2
+	stride, err := checkFrame(frame, height, stride, width)
+	if err != nil {
+		return nil, err
+	}
+	uv := stride * height
+	if height > 0 {
+		if err := checkSize(frame, uv+stride*(ch-1)+2*cw); err != nil {
+			return nil, err
+		}
+	}
+
+	img := image.NewYCbCr(image.Rect(0, 0, width, height), image.YCbCrSubsampleRatio420)
+	copyPlane(img.Y, img.YStride, frame, stride, width, height)
+	for row := 0; row < ch; row++ {
+		line := frame[uv+row*stride:]
+		cbLine := img.Cb[row*img.CStride:]
+		crLine := img.Cr[row*img.CStride:]
+		for x := 0; x < cw; x++ {
+			cbLine[x] = line[2*x+cb]
+			crLine[x] = line[2*x+cr]
+		}
+	}
+	return img, nil
+}
+
+func planar420(frame []byte, width, height, stride int, crFirst bool) (*image.YCbCr, error) {
+	cw, ch := (width+1)/2, (height+1)/2
+	stride, err := checkFrame(frame, height, stride, width)
+	if err != nil {
+		return nil, err
+	}
+	cstride := (stride + 1) / 2
+	first := stride * height
+	second := first + cstride*ch
+	if height > 0 {
+		if err := checkSize(frame, second+cstride*(ch-1)+cw); err != nil {
+			return nil, err
+		}
+	}
+
+	img := image.NewYCbCr(image.Rect(0, 0, width, height), image.YCbCrSubsampleRatio420)
+	cb, cr := first, second
+	if crFirst {
+		cb, cr = second, first
+	}
+	copyPlane(img.Y, img.YStride, frame, stride, width, height)
+	copyPlane(img.Cb, img.CStride, frame[cb:], cstride, cw, ch)
+	copyPlane(img.Cr, img.CStride, frame[cr:], cstride, cw, ch)
+	return img, nil
+}
@@ -0,0 +1,194 @@
+// Reconstruction of color images from raw frames of Bayer sensors,
+// which capture one color component per pixel through a color filter array
+package demosaic
+
+import (
+	"image"
+
+	"github.com/blackjack/webcam"
+	"github.com/blackjack/webcam/convert"
+)
+
+// Interpolation of missing color components
+type Method int
+
+const (
+	// Missing components are averaged from the nearest
+	// pixels of the same color. Fast, but edges get color fringes
+	Bilinear Method = iota
+
+	// Green is interpolated along edges, selected by gradients
+	// of green and of the pixel's own color, red and blue are
+	// interpolated as differences from green. Fewer artifacts
+	// at the cost of roughly twice the time of Bilinear
+	EdgeAware
+)
+
+// Raw frame of a Bayer sensor.
+// Samples are stored in the low Depth bits of Pix
+type Raw struct {
+	Pix []uint16
+	// Distance between lines in samples
+	Stride  int
+	Width   int
+	Height  int
+	Pattern webcam.BayerPattern
+	Depth   int
+}
+
+// Processing parameters
+type Options struct {
+	Method Method
+
+	// Sensor value of black, subtracted from every sample
+	BlackLevel uint16
+
+	// White balance gains, zero means 1
+	RedGain   float64
+	GreenGain float64
+	BlueGain  float64
+}
+
+// Returns raw frame in given Bayer format.
+// Formats with 8-bit samples, with 10 to 16-bit samples stored
+// in 16-bit little-endian words and MIPI packed formats are supported
+func FromFrame(frame []byte, f webcam.FrameFormat) (*Raw, error) {
+	info, ok := f.Format.Info()
+	if !ok || info.Bayer == webcam.BayerNone || info.Compressed {
+		return nil, &convert.UnsupportedFormat{Format: f.Format}
+	}
+
+	p, err := convert.Unpack(frame, f)
+	if err != nil {
+		return nil, err
+	}
+	return &Raw{p.Pix, p.Stride, p.Width, p.Height, info.Bayer, p.Depth}, nil
+}
+
+// Returns color image with 8 bits per component
+func (r *Raw) RGBA(o Options) *image.RGBA {
+	planes := r.demosaic(o)
+	img := image.NewRGBA(image.Rect(0, 0, r.Width, r.Height))
+	for y := 0; y < r.Height; y++ {
+		pix := img.Pix[y*img.Stride:]
+		for x := 0; x < r.Width; x++ {
+			i := y*r.Width + x
+			pix[4*x] = uint8(planes[0][i] >> 8)
+			pix[4*x+1] = uint8(planes[1][i] >> 8)
+			pix[4*x+2] = uint8(planes[2][i] >> 8)
+			pix[4*x+3] = 0xff
+		}
+	}
+	return img
+}
+
+// Returns color image with 16 bits per component
+func (r *Raw) RGBA64(o Options) *image.RGBA64 {
+	planes := r.demosaic(o)
+	img := image.NewRGBA64(image.Rect(0, 0, r.Width, r.Height))
+	for y := 0; y < r.Height; y++ {
+		pix := img.Pix[y*img.Stride:]
+		for x := 0; x < r.Width; x++ {
+			i := y*r.Width + x
+			for c := 0; c < 3; c++ {
+				pix[8*x+2*c] = uint8(planes[c][i] >> 8)
+				pix[8*x+2*c+1] = uint8(planes[c][i])
+			}
+			pix[8*x+6] = 0xff
+			pix[8*x+7] = 0xff
+		}
+	}
+	return img
+}
+
+// Color channels
+const (
+	red = iota
+	green
+	blue
+)
+
+// Returns channel of the color filter at given position
+func (r *Raw) color(x, y int) int {
+	var top, bottom [2]int
+	switch r.Pattern {
+	case webcam.BayerBGGR:
+		top, bottom = [2]int{blue, green}, [2]int{green, red}
+	case webcam.BayerGBRG:
+		top, bottom = [2]int{green, blue}, [2]int{red, green}
+	case webcam.BayerGRBG:
+		top, bottom = [2]int{green, red}, [2]int{blue, green}
+	default:
+		top, bottom = [2]int{red, green}, [2]int{green, blue}
+	}
+	if y%2 == 0 {
+		return top[x%2]
+	}
+	return bottom[x%2]
+}
+
+// Returns R, G and B planes with 16-bit values
+func (r *Raw) demosaic(o Options) [3][]uint16 {
+	p := &mosaic{r, r.normalize(o), r.Width, r.Height}
+
+	var planes [3][]float32
+	if o.Method == EdgeAware {
+		planes = p.edgeAware()
+	} else {
+		planes = p.bilinear()
+	}
+
+	var result [3][]uint16
+	for c := range planes {
+		result[c] = make([]uint16, len(planes[c]))
+		for i, v := range planes[c] {
+			result[c][i] = clamp16(v)
+		}
+	}
+	return result
+}
+
+// Returns samples with black level subtracted and gains applied,
+// scaled to 16-bit range
+func (r *Raw) normalize(o Options) []float32 {
+	white := float64(uint32(1)<<uint(r.Depth) - 1)
+	if r.Depth <= 0 || r.Depth > 16 {
+		white = 0xffff
+	}
+	scale := 1.0
+	if white > float64(o.BlackLevel) {
+		scale = 0xffff / (white - float64(o.BlackLevel))
+	}
+
+	gains := [3]float64{o.RedGain, o.GreenGain, o.BlueGain}
+	var factors [3]float32
+	for c, g := range gains {
+		if g == 0 {
+			g = 1
+		}
+		factors[c] = float32(g * scale)
+	}
+
+	samples := make([]float32, r.Width*r.Height)
+	for y := 0; y < r.Height; y++ {
+		line := r.Pix[y*r.Stride:]
+		for x := 0; x < r.Width; x++ {
+			v := float32(0)
+			if line[x] > o.BlackLevel {
+				v = float32(line[x]-o.BlackLevel) * factors[r.color(x, y)]
+			}
+			samples[y*r.Width+x] = v
+		}
+	}
+	return samples
+}
+
+func clamp16(v float32) uint16 {
+	if v <= 0 {
+		return 0
+	}
+	if v >= 0xffff {
+		return 0xffff
+	}
+	return uint16(v + 0.5)
+}
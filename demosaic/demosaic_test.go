@@ -0,0 +1,153 @@
+package demosaic
+
+import (
+	"fmt"
+	"image/color"
+	"testing"
+
+	"github.com/blackjack/webcam"
+)
+
+// Color filters of 2x2 cells, top line first
+var patterns = map[webcam.BayerPattern]string{
+	webcam.BayerBGGR: "BGGR",
+	webcam.BayerGBRG: "GBRG",
+	webcam.BayerGRBG: "GRBG",
+	webcam.BayerRGGB: "RGGB",
+}
+
+var methods = map[Method]string{
+	Bilinear:  "Bilinear",
+	EdgeAware: "EdgeAware",
+}
+
+// Returns 8-bit raw frame sampling given scene through the color filter array
+func mosaicOf(pattern webcam.BayerPattern, width, height int, scene func(x, y int) color.RGBA) *Raw {
+	cells := patterns[pattern]
+	r := &Raw{make([]uint16, width*height), width, width, height, pattern, 8}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			c := scene(x, y)
+			switch cells[(y%2)*2+x%2] {
+			case 'R':
+				r.Pix[y*width+x] = uint16(c.R)
+			case 'G':
+				r.Pix[y*width+x] = uint16(c.G)
+			case 'B':
+				r.Pix[y*width+x] = uint16(c.B)
+			}
+		}
+	}
+	return r
+}
+
+// Returns number of pixels differing from the scene
+func mismatches(t *testing.T, r *Raw, m Method, scene func(x, y int) color.RGBA, report bool) int {
+	t.Helper()
+	img := r.RGBA(Options{Method: m})
+	img64 := r.RGBA64(Options{Method: m})
+
+	count := 0
+	for y := 0; y < r.Height; y++ {
+		for x := 0; x < r.Width; x++ {
+			want := scene(x, y)
+			want64 := color.RGBA64{uint16(want.R) * 257, uint16(want.G) * 257, uint16(want.B) * 257, 0xffff}
+			got, got64 := img.RGBAAt(x, y), img64.RGBA64At(x, y)
+			if got == want && got64 == want64 {
+				continue
+			}
+			count++
+			if report {
+				t.Errorf("pixel %d,%d is %v (%v), expected %v", x, y, got, got64, want)
+			}
+		}
+	}
+	return count
+}
+
+func TestFlatField(t *testing.T) {
+	flat := func(x, y int) color.RGBA {
+		return color.RGBA{200, 120, 40, 0xff}
+	}
+	for pattern, name := range patterns {
+		for m, method := range methods {
+			t.Run(fmt.Sprintf("%s/%s", name, method), func(t *testing.T) {
+				// Odd sizes make the last line and column start a new cell
+				mismatches(t, mosaicOf(pattern, 7, 5, flat), m, flat, true)
+			})
+		}
+	}
+}
+
+func TestEdges(t *testing.T) {
+	level := func(bright bool) color.RGBA {
+		if bright {
+			return color.RGBA{200, 200, 200, 0xff}
+		}
+		return color.RGBA{20, 20, 20, 0xff}
+	}
+	edges := map[string]func(x, y int) color.RGBA{
+		"vertical": func(x, y int) color.RGBA {
+			return level(x >= 4)
+		},
+		"horizontal": func(x, y int) color.RGBA {
+			return level(y >= 4)
+		},
+	}
+
+	for edge, scene := range edges {
+		for pattern, name := range patterns {
+			t.Run(fmt.Sprintf("%s/%s", edge, name), func(t *testing.T) {
+				r := mosaicOf(pattern, 9, 7, scene)
+
+				// Green is interpolated along the edge, so grey scene
+				// is reproduced exactly
+				mismatches(t, r, EdgeAware, scene, true)
+
+				// Bilinear interpolation averages across the edge
+				if mismatches(t, r, Bilinear, scene, false) == 0 {
+					t.Error("Bilinear reproduced the edge exactly, the scene doesn't test anything")
+				}
+			})
+		}
+	}
+}
+
+func TestFromFrame(t *testing.T) {
+	f := webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_SGRBG8, Width: 3, Height: 2, BytesPerLine: 4}
+	r, err := FromFrame([]byte{1, 2, 3, 0xee, 4, 5, 6}, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Pattern != webcam.BayerGRBG || r.Depth != 8 {
+		t.Errorf("got pattern %s and depth %d, expected %s and 8", r.Pattern, r.Depth, webcam.BayerGRBG)
+	}
+	for i, v := range []uint16{1, 2, 3, 4, 5, 6} {
+		if got := r.Pix[(i/3)*r.Stride+i%3]; got != v {
+			t.Errorf("sample %d is %d, expected %d", i, got, v)
+		}
+	}
+}
+
+func TestFromFramePacked(t *testing.T) {
+	f := webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_SRGGB10P, Width: 4, Height: 1}
+	r, err := FromFrame([]byte{0xff, 0x00, 0x80, 0x01, 0xe4}, f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Pattern != webcam.BayerRGGB || r.Depth != 10 {
+		t.Errorf("got pattern %s and depth %d, expected %s and 10", r.Pattern, r.Depth, webcam.BayerRGGB)
+	}
+	for i, v := range []uint16{0x3fc, 0x001, 0x202, 0x007} {
+		if r.Pix[i] != v {
+			t.Errorf("sample %d is %#x, expected %#x", i, r.Pix[i], v)
+		}
+	}
+}
+
+func TestFromFrameUnsupported(t *testing.T) {
+	_, err := FromFrame(nil, webcam.FrameFormat{Format: webcam.V4L2_PIX_FMT_YUYV})
+	if err == nil {
+		t.Error("non-Bayer format was accepted")
+	}
+}
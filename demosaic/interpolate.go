@@ -0,0 +1,124 @@
+package demosaic
+
+// Normalized samples of a raw frame
+type mosaic struct {
+	raw     *Raw
+	samples []float32
+	width   int
+	height  int
+}
+
+// Returns sample at given position, positions outside of the frame
+// are mirrored at its edges, which keeps the color filter pattern
+func (m *mosaic) at(plane []float32, x, y int) float32 {
+	return plane[reflect(y, m.height)*m.width+reflect(x, m.width)]
+}
+
+func reflect(i, n int) int {
+	if n == 1 {
+		return 0
+	}
+	for i < 0 || i >= n {
+		if i < 0 {
+			i = -i
+		} else {
+			i = 2*(n-1) - i
+		}
+	}
+	return i
+}
+
+func (m *mosaic) bilinear() [3][]float32 {
+	var planes [3][]float32
+	for c := range planes {
+		planes[c] = make([]float32, len(m.samples))
+	}
+
+	s := m.samples
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			i := y*m.width + x
+			c := m.raw.color(x, y)
+			planes[c][i] = s[i]
+
+			if c == green {
+				planes[m.raw.color(x+1, y)][i] = (m.at(s, x-1, y) + m.at(s, x+1, y)) / 2
+				planes[m.raw.color(x, y+1)][i] = (m.at(s, x, y-1) + m.at(s, x, y+1)) / 2
+				continue
+			}
+
+			planes[green][i] = (m.at(s, x-1, y) + m.at(s, x+1, y) + m.at(s, x, y-1) + m.at(s, x, y+1)) / 4
+			planes[red+blue-c][i] = (m.at(s, x-1, y-1) + m.at(s, x+1, y-1) + m.at(s, x-1, y+1) + m.at(s, x+1, y+1)) / 4
+		}
+	}
+	return planes
+}
+
+func (m *mosaic) edgeAware() [3][]float32 {
+	var planes [3][]float32
+	for c := range planes {
+		planes[c] = make([]float32, len(m.samples))
+	}
+
+	// Green is interpolated first along the direction with smaller
+	// gradient, corrected by the second derivative of the own color
+	s := m.samples
+	g := planes[green]
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			i := y*m.width + x
+			if m.raw.color(x, y) == green {
+				g[i] = s[i]
+				continue
+			}
+
+			left, right := m.at(s, x-1, y), m.at(s, x+1, y)
+			up, down := m.at(s, x, y-1), m.at(s, x, y+1)
+			lapH := 2*s[i] - m.at(s, x-2, y) - m.at(s, x+2, y)
+			lapV := 2*s[i] - m.at(s, x, y-2) - m.at(s, x, y+2)
+			gradH := abs(left-right) + abs(lapH)
+			gradV := abs(up-down) + abs(lapV)
+
+			gh := (left+right)/2 + lapH/4
+			gv := (up+down)/2 + lapV/4
+			switch {
+			case gradH < gradV:
+				g[i] = gh
+			case gradV < gradH:
+				g[i] = gv
+			default:
+				g[i] = (gh + gv) / 2
+			}
+		}
+	}
+
+	// Red and blue are interpolated as differences from green,
+	// which change slower than colors themselves
+	diff := make([]float32, len(s))
+	for i := range s {
+		diff[i] = s[i] - g[i]
+	}
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			i := y*m.width + x
+			c := m.raw.color(x, y)
+
+			if c == green {
+				planes[m.raw.color(x+1, y)][i] = g[i] + (m.at(diff, x-1, y)+m.at(diff, x+1, y))/2
+				planes[m.raw.color(x, y+1)][i] = g[i] + (m.at(diff, x, y-1)+m.at(diff, x, y+1))/2
+				continue
+			}
+
+			planes[c][i] = s[i]
+			planes[red+blue-c][i] = g[i] + (m.at(diff, x-1, y-1)+m.at(diff, x+1, y-1)+m.at(diff, x-1, y+1)+m.at(diff, x+1, y+1))/4
+		}
+	}
+	return planes
+}
+
+func abs(v float32) float32 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -1,8 +1,90 @@
 package webcam
 
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Returned by named control accessors when the device lacks the control
+var ErrControlUnsupported = errors.New("Control is not supported by the device")
+
 // Timeout error
 type Timeout struct{}
 
 func (e *Timeout) Error() string {
 	return "Timeout occured"
 }
+
+// Framerate control unsupported error
+type FramerateUnsupported struct{}
+
+func (e *FramerateUnsupported) Error() string {
+	return "Device does not support framerate control"
+}
+
+// Error returned when device refuses to start streaming.
+// Err is the error reported by VIDIOC_STREAMON, e.g. unix.ENOSPC
+// when there is not enough USB bandwidth for selected mode
+type StreamingError struct {
+	Err error
+}
+
+func (e *StreamingError) Error() string {
+	return "Failed to start streaming: " + e.Err.Error()
+}
+
+// Error returned with a damaged frame in FrameValidationFlag mode.
+// The frame is still returned and must be released
+type CorruptFrame struct {
+	Index  uint32
+	Reason string
+}
+
+func (e *CorruptFrame) Error() string {
+	return "Corrupt frame: " + e.Reason
+}
+
+// Error returned by extended control API.
+// Index is the position of the control which caused the error
+type ExtControlError struct {
+	Index int
+	ID    ControlID
+	Err   error
+}
+
+func (e *ExtControlError) Error() string {
+	return fmt.Sprintf("Control %08x (#%d): %s", uint32(e.ID), e.Index, e.Err)
+}
+
+// Error returned when a control value is refused by validation,
+// see SetControlValidation
+type ControlValueError struct {
+	ID     ControlID
+	Name   string
+	Value  int32
+	Reason string
+}
+
+func (e *ControlValueError) Error() string {
+	return fmt.Sprintf("Cannot set control '%s' (%08x) to %d: %s", e.Name, uint32(e.ID), e.Value, e.Reason)
+}
+
+// Errors of controls which couldn't be set by a batch operation
+// like ApplyProfile. Other controls of the batch are set
+type ControlErrors map[ControlID]error
+
+func (e ControlErrors) Error() string {
+	ids := make([]ControlID, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	msgs := make([]string, len(ids))
+	for i, id := range ids {
+		msgs[i] = fmt.Sprintf("control %08x: %s", uint32(id), e[id])
+	}
+	return "Failed to set controls: " + strings.Join(msgs, "; ")
+}
@@ -0,0 +1,287 @@
+package webcam
+
+// Size of the channel events are delivered to.
+// WaitForFrame never blocks on event delivery, so events are dropped
+// if the channel is full. Dropped events are counted, see GetDroppedEvents.
+// A dropped source change or end of stream event means that the stream
+// may have changed unnoticed, so consumers should check the count
+// and query the image format again if it grows
+const eventQueueSize = 64
+
+// Change of a control reported by the device, e.g. exposure
+// changed by auto exposure or brightness changed by another application.
+// Changes is a combination of V4L2_EVENT_CTRL_CH_* values telling
+// whether value, flags or range of the control has changed
+type ControlEvent struct {
+	ID      ControlID
+	Changes uint32
+	Type    uint32
+	Value   int64
+	Flags   uint32
+	Min     int32
+	Max     int32
+	Step    int32
+	Default int32
+}
+
+// Subscribe to changes of given controls.
+// Events are dequeued by WaitForFrame and delivered to the returned
+// channel, which is the same for all subscriptions and is closed by Close.
+// Changes made by this application itself are not reported
+func (w *Webcam) SubscribeControlEvents(ids ...ControlID) (<-chan ControlEvent, error) {
+	for _, id := range ids {
+		err := subscribeEvent(w.fd, V4L2_EVENT_CTRL, uint32(id), 0)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	w.eventsLock.Lock()
+	defer w.eventsLock.Unlock()
+	if w.controlEvents == nil {
+		w.controlEvents = make(chan ControlEvent, eventQueueSize)
+	}
+	return w.controlEvents, nil
+}
+
+// Stop receiving changes of given controls
+func (w *Webcam) UnsubscribeControlEvents(ids ...ControlID) error {
+	for _, id := range ids {
+		err := unsubscribeEvent(w.fd, V4L2_EVENT_CTRL, uint32(id))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Source change or end of stream reported by the device.
+// Type is either V4L2_EVENT_SOURCE_CHANGE or V4L2_EVENT_EOS.
+// For source changes Changes is a combination of V4L2_EVENT_SRC_CH_*
+// values, e.g. resolution change of HDMI input.
+// If stream was restarted automatically, Restarted is set and
+// Format, Width and Height describe the new image format,
+// otherwise Err is the reason of failure
+type StreamEvent struct {
+	Type      uint32
+	Changes   uint32
+	Restarted bool
+	Format    PixelFormat
+	Width     uint32
+	Height    uint32
+	Err       error
+}
+
+// Subscribe to source change and end of stream events.
+// Events are dequeued by WaitForFrame and delivered to the returned
+// channel, which is closed by Close.
+// If autoRestart is set, streaming is restarted on source change
+// with new image format: formats and frame sizes of the new source are
+// negotiated with preferences given to the last Negotiate or
+// StartStreamingWithFallback call, or with the current pixel format
+// otherwise. A mode StartStreamingWithFallback stepped down to is kept
+// if the new source supports it, and streaming is started with
+// StartStreamingWithFallback, so it can step down again.
+// Returns an error only if device supports neither of the events
+func (w *Webcam) SubscribeStreamEvents(autoRestart bool) (<-chan StreamEvent, error) {
+	errSource := subscribeEvent(w.fd, V4L2_EVENT_SOURCE_CHANGE, 0, 0)
+	errEOS := subscribeEvent(w.fd, V4L2_EVENT_EOS, 0, 0)
+	if errSource != nil && errEOS != nil {
+		return nil, errSource
+	}
+
+	w.eventsLock.Lock()
+	defer w.eventsLock.Unlock()
+	w.autoRestart = autoRestart
+	if w.streamEvents == nil {
+		w.streamEvents = make(chan StreamEvent, eventQueueSize)
+	}
+	return w.streamEvents, nil
+}
+
+// Stop receiving source change and end of stream events
+func (w *Webcam) UnsubscribeStreamEvents() error {
+	w.eventsLock.Lock()
+	w.autoRestart = false
+	w.eventsLock.Unlock()
+	errSource := unsubscribeEvent(w.fd, V4L2_EVENT_SOURCE_CHANGE, 0)
+	errEOS := unsubscribeEvent(w.fd, V4L2_EVENT_EOS, 0)
+	if errSource != nil && errEOS != nil {
+		return errSource
+	}
+	return nil
+}
+
+// Dequeues all pending events and delivers them to subscribers.
+// Returns true if waiting for frame should be stopped
+// to restart streaming after source change
+func (w *Webcam) dequeueEvents() bool {
+	for {
+		event, err := dequeueEvent(w.fd)
+		if err != nil {
+			return w.hasSourceChange()
+		}
+
+		switch event._type {
+		case V4L2_EVENT_CTRL:
+			e, err := parseControlEvent(event)
+			if err == nil {
+				w.sendControlEvent(e)
+			}
+
+		case V4L2_EVENT_SOURCE_CHANGE:
+			e := StreamEvent{Type: event._type, Changes: NativeByteOrder.Uint32(event.union.data[:4])}
+			if !w.deferSourceChange(&e) {
+				w.sendStreamEvent(e)
+			}
+
+		case V4L2_EVENT_EOS:
+			w.sendStreamEvent(StreamEvent{Type: event._type})
+		}
+
+		if event.pending == 0 {
+			return w.hasSourceChange()
+		}
+	}
+}
+
+// Returns number of events dropped because their channel was full
+func (w *Webcam) GetDroppedEvents() uint64 {
+	w.eventsLock.Lock()
+	defer w.eventsLock.Unlock()
+	return w.droppedEvents
+}
+
+// Sends are guarded by eventsLock, so that Close can't close
+// the channel while an event is being delivered
+func (w *Webcam) sendControlEvent(e ControlEvent) {
+	w.eventsLock.Lock()
+	defer w.eventsLock.Unlock()
+	if w.controlEvents == nil {
+		return
+	}
+	select {
+	case w.controlEvents <- e:
+	default:
+		w.droppedEvents++
+	}
+}
+
+func (w *Webcam) sendStreamEvent(e StreamEvent) {
+	w.eventsLock.Lock()
+	defer w.eventsLock.Unlock()
+	if w.streamEvents == nil {
+		return
+	}
+	select {
+	case w.streamEvents <- e:
+	default:
+		w.droppedEvents++
+	}
+}
+
+func (w *Webcam) hasEventSubscribers() bool {
+	w.eventsLock.Lock()
+	defer w.eventsLock.Unlock()
+	return w.controlEvents != nil || w.streamEvents != nil
+}
+
+// Keeps source change to be delivered after automatic restart.
+// Returns false if streaming isn't restarted automatically
+func (w *Webcam) deferSourceChange(e *StreamEvent) bool {
+	w.eventsLock.Lock()
+	defer w.eventsLock.Unlock()
+	if !w.autoRestart || !w.streaming {
+		return false
+	}
+	w.sourceChange = e
+	return true
+}
+
+func (w *Webcam) hasSourceChange() bool {
+	w.eventsLock.Lock()
+	defer w.eventsLock.Unlock()
+	return w.sourceChange != nil
+}
+
+// Closes event channels, events dequeued afterwards are discarded
+func (w *Webcam) closeEvents() {
+	w.eventsLock.Lock()
+	defer w.eventsLock.Unlock()
+	if w.controlEvents != nil {
+		close(w.controlEvents)
+		w.controlEvents = nil
+	}
+	if w.streamEvents != nil {
+		close(w.streamEvents)
+		w.streamEvents = nil
+	}
+}
+
+// Stops streaming, applies new image format and starts streaming again.
+// Returns an error if streaming couldn't be restarted
+func (w *Webcam) restartAfterSourceChange() error {
+	w.eventsLock.Lock()
+	e := w.sourceChange
+	w.sourceChange = nil
+	w.eventsLock.Unlock()
+
+	err := w.restartStreaming()
+	e.Err = err
+	if err == nil {
+		e.Restarted = true
+		e.Format, e.Width, e.Height, e.Err = w.GetImageFormat()
+	}
+	w.sendStreamEvent(*e)
+	return err
+}
+
+func (w *Webcam) restartStreaming() error {
+	if w.streaming {
+		w.streaming = false
+		err := stopStreaming(w.fd)
+		if err != nil {
+			return err
+		}
+		err = w.releaseBuffers()
+		if err != nil {
+			return err
+		}
+	}
+
+	// Modes are enumerated again, as they change with the source
+	var p FormatPreferences
+	if w.preferences != nil {
+		p = *w.preferences
+	} else {
+		f, _, _, err := w.GetImageFormat()
+		if err != nil {
+			return err
+		}
+		p.Formats = []PixelFormat{f}
+	}
+
+	fallback := w.fallbackMode
+	n, err := w.Negotiate(p)
+	if err != nil {
+		return err
+	}
+	// Negotiated mode may need more bandwidth than available, the
+	// one fallback stepped down to is kept if it is less demanding
+	// and still supported
+	negotiated := modeCandidate{format: n.Format, width: n.Width, height: n.Height, interval: n.Interval}
+	if fallback != nil && fallback.pixelRate() < negotiated.pixelRate() {
+		code, width, height := uint32(fallback.format), fallback.width, fallback.height
+		err = tryImageFormat(w.fd, &code, &width, &height)
+		if err == nil && PixelFormat(code) == fallback.format && width == fallback.width && height == fallback.height {
+			err = w.applyMode(*fallback)
+			if err != nil {
+				return err
+			}
+			w.fallbackMode = fallback
+		}
+	}
+
+	_, err = w.StartStreamingWithFallback(p)
+	return err
+}
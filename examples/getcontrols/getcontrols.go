@@ -2,13 +2,16 @@
 package main
 
 import (
+	"encoding/json"
 	"flag"
 	"fmt"
+	"os"
 
 	"github.com/blackjack/webcam"
 )
 
 var device = flag.String("input", "/dev/video0", "Input video device")
+var asJSON = flag.Bool("json", false, "Print full capability matrix as JSON")
 
 func main() {
 	flag.Parse()
@@ -18,23 +21,49 @@ func main() {
 	}
 	defer cam.Close()
 
-	fmap := cam.GetSupportedFormats()
+	if *asJSON {
+		m, err := cam.GetCapabilityMatrix()
+		if err != nil {
+			panic(err.Error())
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(m); err != nil {
+			panic(err.Error())
+		}
+		return
+	}
+
 	fmt.Println("Available Formats: ")
-	for p, s := range fmap {
-		var pix []byte
-		for i := 0; i < 4; i++ {
-			pix = append(pix, byte(p>>uint(i*8)))
+	for _, d := range cam.GetFormatDescriptions() {
+		p := d.Format
+		fmt.Printf("ID:%08x ('%s') %s", uint32(p), p, d.Description)
+		if d.Compressed() {
+			fmt.Printf(" [compressed]")
+		}
+		if d.Emulated() {
+			fmt.Printf(" [emulated]")
 		}
-		fmt.Printf("ID:%08x ('%s') %s\n   ", p, pix, s)
+		fmt.Printf("\n   ")
 		for _, fs := range cam.GetSupportedFrameSizes(p) {
 			fmt.Printf(" %s", fs.GetString())
 		}
 		fmt.Printf("\n")
 	}
 
-	cmap := cam.GetControls()
 	fmt.Println("Available controls: ")
-	for id, c := range cmap {
-		fmt.Printf("ID:%08x %-32s  Min: %4d  Max: %5d\n", id, c.Name, c.Min, c.Max)
+	for _, class := range cam.GetControls() {
+		fmt.Printf("%s\n", class.Name)
+		for _, c := range class.Controls {
+			printControl(c.ID, c.Control)
+		}
+	}
+}
+
+func printControl(id webcam.ControlID, c webcam.Control) {
+	fmt.Printf("  ID:%08x %-32s  Type: %3d  Min: %4d  Max: %5d  Step: %3d  Default: %5d  Flags: %04x\n",
+		id, c.Name, c.Type, c.Min, c.Max, c.Step, c.Default, c.Flags)
+	for _, item := range c.Menu {
+		fmt.Printf("      %d: %s\n", item.Index, item.Name)
 	}
 }
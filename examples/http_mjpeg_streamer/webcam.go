@@ -13,45 +13,25 @@ import (
 	"net/http"
 	"net/textproto"
 	"os"
-	"sort"
 	"strconv"
 	"time"
 
 	"github.com/blackjack/webcam"
+	"github.com/blackjack/webcam/convert"
 )
 
-const (
-	V4L2_PIX_FMT_PJPG = 0x47504A50
-	V4L2_PIX_FMT_YUYV = 0x56595559
-)
-
-type FrameSizes []webcam.FrameSize
-
-func (slice FrameSizes) Len() int {
-	return len(slice)
-}
-
-//For sorting purposes
-func (slice FrameSizes) Less(i, j int) bool {
-	ls := slice[i].MaxWidth * slice[i].MaxHeight
-	rs := slice[j].MaxWidth * slice[j].MaxHeight
-	return ls < rs
-}
-
-//For sorting purposes
-func (slice FrameSizes) Swap(i, j int) {
-	slice[i], slice[j] = slice[j], slice[i]
-}
-
 var supportedFormats = map[webcam.PixelFormat]bool{
-	V4L2_PIX_FMT_PJPG: true,
-	V4L2_PIX_FMT_YUYV: true,
+	webcam.V4L2_PIX_FMT_MJPEG: true,
+	webcam.V4L2_PIX_FMT_YUYV:  true,
+	webcam.V4L2_PIX_FMT_Z16:   true,
+	webcam.V4L2_PIX_FMT_Y8I:   true,
+	webcam.V4L2_PIX_FMT_Y12I:  true,
 }
 
 func main() {
 	dev := flag.String("d", "/dev/video0", "video device to use")
-	fmtstr := flag.String("f", "", "video format to use, default first supported")
-	szstr := flag.String("s", "", "frame size to use, default largest one")
+	fmtstr := flag.String("f", "", "video format to use, description or fourcc, default first supported")
+	szstr := flag.String("s", "", "frame size to use, e.g. 1280x720, default largest one")
 	single := flag.Bool("m", false, "single image http mode, default mjpeg video")
 	addr := flag.String("l", ":8080", "addr to listien")
 	fps := flag.Bool("p", false, "print fps info")
@@ -64,67 +44,59 @@ func main() {
 	defer cam.Close()
 
 	// select pixel format
-	format_desc := cam.GetSupportedFormats()
+	formats := cam.GetFormatDescriptions()
 
 	fmt.Println("Available formats:")
-	for _, s := range format_desc {
-		fmt.Fprintln(os.Stderr, s)
+	for _, d := range formats {
+		fmt.Fprintln(os.Stderr, d.Description)
 	}
 
 	var format webcam.PixelFormat
-FMT:
-	for f, s := range format_desc {
-		if *fmtstr == "" {
-			if supportedFormats[f] {
-				format = f
-				break FMT
-			}
-
-		} else if *fmtstr == s {
-			if !supportedFormats[f] {
-				log.Println(format_desc[f], "format is not supported, exiting")
+	for _, d := range formats {
+		if *fmtstr == d.Description || *fmtstr == d.Format.String() {
+			if !supportedFormats[d.Format] {
+				log.Println(d.Description, "format is not supported, exiting")
 				return
 			}
-			format = f
+			format = d.Format
 			break
 		}
 	}
-	if format == 0 {
+	if *fmtstr != "" && format == 0 {
 		log.Println("No format found, exiting")
 		return
 	}
 
-	// select frame size
-	frames := FrameSizes(cam.GetSupportedFrameSizes(format))
-	sort.Sort(frames)
-
-	fmt.Fprintln(os.Stderr, "Supported frame sizes for format", format_desc[format])
-	for _, f := range frames {
-		fmt.Fprintln(os.Stderr, f.GetString())
+	// select pixel format and frame size
+	prefs := webcam.FormatPreferences{
+		Formats: []webcam.PixelFormat{webcam.V4L2_PIX_FMT_MJPEG, webcam.V4L2_PIX_FMT_YUYV},
 	}
-	var size *webcam.FrameSize
-	if *szstr == "" {
-		size = &frames[len(frames)-1]
-	} else {
-		for _, f := range frames {
-			if *szstr == f.GetString() {
-				size = &f
-			}
-		}
+	if format != 0 {
+		prefs.Formats = []webcam.PixelFormat{format}
 	}
-	if size == nil {
-		log.Println("No matching frame size, exiting")
-		return
+	if *szstr != "" {
+		var sw, sh uint32
+		if _, err := fmt.Sscanf(*szstr, "%dx%d", &sw, &sh); err != nil {
+			log.Println("Invalid frame size", *szstr, "exiting")
+			return
+		}
+		prefs.MinWidth, prefs.MaxWidth = sw, sw
+		prefs.MinHeight, prefs.MaxHeight = sh, sh
 	}
 
-	fmt.Fprintln(os.Stderr, "Requesting", format_desc[format], size.GetString())
-	f, w, h, err := cam.SetImageFormat(format, uint32(size.MaxWidth), uint32(size.MaxHeight))
+	mode, err := cam.Negotiate(prefs)
 	if err != nil {
-		log.Println("SetImageFormat return error", err)
+		log.Println("Negotiate return error", err)
 		return
-
 	}
-	fmt.Fprintf(os.Stderr, "Resulting image format: %s %dx%d\n", format_desc[f], w, h)
+	for _, r := range mode.Reasons {
+		fmt.Fprintln(os.Stderr, r)
+	}
+	f, w, h := mode.Format, mode.Width, mode.Height
+	fmt.Fprintf(os.Stderr, "Resulting image format: %s %dx%d\n", f, w, h)
+
+	// partial frames are common when USB bandwidth is tight
+	cam.SetFrameValidation(webcam.FrameValidationDrop)
 
 	// start streaming
 	err = cam.StartStreaming()
@@ -138,7 +110,12 @@ FMT:
 		fi   chan []byte        = make(chan []byte)
 		back chan struct{}      = make(chan struct{})
 	)
-	go encodeToImage(cam, back, fi, li, w, h, f)
+	layout, err := cam.GetFrameFormat()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	go encodeToImage(cam, back, fi, li, layout)
 	if *single {
 		go httpImage(*addr, li)
 	} else {
@@ -177,7 +154,8 @@ FMT:
 			fr++
 			if *fps {
 				if d := time.Since(start); d > time.Second*10 {
-					fmt.Println(float64(fr)/(float64(d)/float64(time.Second)), "fps")
+					stats := cam.GetStreamStats()
+					fmt.Println(float64(fr)/(float64(d)/float64(time.Second)), "fps,", stats.Dropped, "corrupt frames dropped")
 					start = time.Now()
 					fr = 0
 				}
@@ -192,12 +170,9 @@ FMT:
 	}
 }
 
-func encodeToImage(wc *webcam.Webcam, back chan struct{}, fi chan []byte, li chan *bytes.Buffer, w, h uint32, format webcam.PixelFormat) {
+func encodeToImage(wc *webcam.Webcam, back chan struct{}, fi chan []byte, li chan *bytes.Buffer, layout webcam.FrameFormat) {
 
-	var (
-		frame []byte
-		img   image.Image
-	)
+	var frame []byte
 	for {
 		bframe := <-fi
 		// copy frame
@@ -207,26 +182,25 @@ func encodeToImage(wc *webcam.Webcam, back chan struct{}, fi chan []byte, li cha
 		copy(frame, bframe)
 		back <- struct{}{}
 
-		switch format {
-		case V4L2_PIX_FMT_YUYV:
-			yuyv := image.NewYCbCr(image.Rect(0, 0, int(w), int(h)), image.YCbCrSubsampleRatio422)
-			for i := range yuyv.Cb {
-				ii := i * 4
-				yuyv.Y[i*2] = frame[ii]
-				yuyv.Y[i*2+1] = frame[ii+2]
-				yuyv.Cb[i] = frame[ii+1]
-				yuyv.Cr[i] = frame[ii+3]
-
-			}
-			img = yuyv
-		default:
-			log.Fatal("invalid format ?")
-		}
-		//convert to jpeg
 		buf := &bytes.Buffer{}
-		if err := jpeg.Encode(buf, img, nil); err != nil {
-			log.Fatal(err)
-			return
+		if layout.Format == webcam.V4L2_PIX_FMT_MJPEG {
+			// already jpeg, only missing tables are added
+			fixed, err := convert.FixMJPEG(frame[:len(bframe)])
+			if err != nil {
+				log.Println(err)
+				continue
+			}
+			buf.Write(fixed)
+		} else {
+			img, err := toImage(frame[:len(bframe)], layout)
+			if err != nil {
+				log.Fatal(err)
+			}
+			//convert to jpeg
+			if err := jpeg.Encode(buf, img, nil); err != nil {
+				log.Fatal(err)
+				return
+			}
 		}
 
 		const N = 50
@@ -247,6 +221,27 @@ func encodeToImage(wc *webcam.Webcam, back chan struct{}, fi chan []byte, li cha
 	}
 }
 
+// Converts frame to image, depth is shown in false colors
+// and only left image of stereo pairs is shown
+func toImage(frame []byte, layout webcam.FrameFormat) (image.Image, error) {
+	width, height, stride := int(layout.Width), int(layout.Height), int(layout.BytesPerLine)
+	switch layout.Format {
+	case webcam.V4L2_PIX_FMT_Z16:
+		depth, err := convert.Z16(frame, width, height, stride, 1)
+		if err != nil {
+			return nil, err
+		}
+		return convert.FalseColor(depth, 0, 0), nil
+	case webcam.V4L2_PIX_FMT_Y8I:
+		left, _, err := convert.Y8I(frame, width, height, stride)
+		return left, err
+	case webcam.V4L2_PIX_FMT_Y12I:
+		left, _, err := convert.Y12I(frame, width, height, stride)
+		return left, err
+	}
+	return convert.ToRGBA(frame, layout)
+}
+
 func httpImage(addr string, li chan *bytes.Buffer) {
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
 		log.Println("connect from", r.RemoteAddr, r.URL)
@@ -4,12 +4,16 @@
 // If your device supports motion formats (e.g. H264 or MJPEG) you can
 // use it's output as a video stream.
 // Example usage: go run stdout_streamer.go | vlc -
+// Use -a flag to skip interactive format selection
 package main
 
 import "github.com/blackjack/webcam"
 import "os"
 import "fmt"
 import "sort"
+import "flag"
+
+var auto = flag.Bool("a", false, "select format and largest frame size automatically")
 
 func readChoice(s string) int {
 	var i int
@@ -43,28 +47,16 @@ func (slice FrameSizes) Swap(i, j int) {
 	slice[i], slice[j] = slice[j], slice[i]
 }
 
-func main() {
-	cam, err := webcam.Open("/dev/video0")
-	if err != nil {
-		panic(err.Error())
-	}
-	defer cam.Close()
-
-	format_desc := cam.GetSupportedFormats()
-	var formats []webcam.PixelFormat
-	for f := range format_desc {
-		formats = append(formats, f)
-	}
-
+func chooseFormat(cam *webcam.Webcam, formats []webcam.FormatDescription) {
 	println("Available formats: ")
 	for i, value := range formats {
-		fmt.Fprintf(os.Stderr, "[%d] %s\n", i+1, format_desc[value])
+		fmt.Fprintf(os.Stderr, "[%d] %s (%s)\n", i+1, value.Description, value.Format)
 	}
 
 	choice := readChoice(fmt.Sprintf("Choose format [1-%d]: ", len(formats)))
-	format := formats[choice-1]
+	format := formats[choice-1].Format
 
-	fmt.Fprintf(os.Stderr, "Supported frame sizes for format %s\n", format_desc[format])
+	fmt.Fprintf(os.Stderr, "Supported frame sizes for format %s\n", format)
 	frames := FrameSizes(cam.GetSupportedFrameSizes(format))
 	sort.Sort(frames)
 
@@ -79,7 +71,26 @@ func main() {
 	if err != nil {
 		panic(err.Error())
 	} else {
-		fmt.Fprintf(os.Stderr, "Resulting image format: %s (%dx%d)\n", format_desc[f], w, h)
+		fmt.Fprintf(os.Stderr, "Resulting image format: %s (%dx%d)\n", f, w, h)
+	}
+}
+
+func main() {
+	flag.Parse()
+	cam, err := webcam.Open("/dev/video0")
+	if err != nil {
+		panic(err.Error())
+	}
+	defer cam.Close()
+
+	if *auto {
+		mode, err := cam.Negotiate(webcam.FormatPreferences{})
+		if err != nil {
+			panic(err.Error())
+		}
+		fmt.Fprintf(os.Stderr, "Resulting image format: %s (%dx%d)\n", mode.Format, mode.Width, mode.Height)
+	} else {
+		chooseFormat(cam, cam.GetFormatDescriptions())
 	}
 
 	println("Press Enter to start streaming")
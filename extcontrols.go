@@ -0,0 +1,166 @@
+package webcam
+
+import (
+	"fmt"
+	"runtime"
+	"unsafe"
+)
+
+// Typed value of a control used by the extended control API.
+// Value holds one of:
+//
+//	int32    for integer, menu, bitmask and button controls
+//	int64    for 64-bit integer controls
+//	bool     for boolean controls
+//	string   for string controls
+//	[]byte   for U8 arrays and other compound controls
+//	[]uint16 for U16 arrays
+//	[]uint32 for U32 arrays
+type ControlValue struct {
+	ID    ControlID
+	Value interface{}
+}
+
+// Get values of several controls at once.
+// Values are returned in the same order as IDs
+func (w *Webcam) GetExtControls(ids ...ControlID) ([]ControlValue, error) {
+	values := make([]ControlValue, len(ids))
+	for i, id := range ids {
+		c_type, elemSize, elems, err := controlLayout(w.fd, uint32(id))
+		if err != nil {
+			return nil, &ExtControlError{i, id, err}
+		}
+		values[i] = ControlValue{id, zeroControlValue(c_type, elemSize, elems)}
+	}
+
+	err := w.extControls(VIDIOC_G_EXT_CTRLS, values)
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// Set values of several controls atomically.
+// Either all values are applied or none of them.
+// Values adjusted by the driver are written back to the slice
+func (w *Webcam) SetExtControls(values []ControlValue) error {
+	return w.extControls(VIDIOC_S_EXT_CTRLS, values)
+}
+
+// Validate values of several controls without applying them.
+// Values adjusted by the driver are written back to the slice
+func (w *Webcam) TryExtControls(values []ControlValue) error {
+	return w.extControls(VIDIOC_TRY_EXT_CTRLS, values)
+}
+
+func (w *Webcam) extControls(request uintptr, values []ControlValue) error {
+	ctrls := make([]v4l2_ext_control, len(values))
+	payloads := make([]interface{}, len(values))
+
+	for i, v := range values {
+		ctrls[i].id = uint32(v.ID)
+		payload, err := encodeControlValue(&ctrls[i], v.Value)
+		if err != nil {
+			return &ExtControlError{i, v.ID, err}
+		}
+		payloads[i] = payload
+	}
+
+	errorIdx, err := extControls(w.fd, request, ctrls)
+	runtime.KeepAlive(payloads)
+
+	if err != nil {
+		if int(errorIdx) < len(values) {
+			return &ExtControlError{int(errorIdx), values[errorIdx].ID, err}
+		}
+		return err
+	}
+
+	for i := range values {
+		values[i].Value = decodeControlValue(&ctrls[i], values[i].Value, payloads[i])
+	}
+	return nil
+}
+
+func zeroControlValue(c_type, elemSize, elems uint32) interface{} {
+	switch c_type {
+	case V4L2_CTRL_TYPE_BOOLEAN:
+		return false
+	case V4L2_CTRL_TYPE_INTEGER64:
+		return int64(0)
+	case V4L2_CTRL_TYPE_STRING:
+		// Decoding relies on buffer length, not on the string
+		return string(make([]byte, elemSize*elems))
+	case V4L2_CTRL_TYPE_U16:
+		return make([]uint16, elems)
+	case V4L2_CTRL_TYPE_U32:
+		return make([]uint32, elems)
+	}
+	if c_type >= V4L2_CTRL_COMPOUND_TYPES {
+		return make([]byte, elemSize*elems)
+	}
+	return int32(0)
+}
+
+// Fills value or payload pointer of a control.
+// Returns payload buffer which must be kept alive during ioctl
+func encodeControlValue(c *v4l2_ext_control, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case int32:
+		NativeByteOrder.PutUint32(c.union[:], uint32(v))
+	case bool:
+		if v {
+			NativeByteOrder.PutUint32(c.union[:], 1)
+		}
+	case int64:
+		NativeByteOrder.PutUint64(c.union[:], uint64(v))
+	case string:
+		buf := make([]byte, len(v)+1)
+		copy(buf, v)
+		c.size = uint32(len(buf))
+		c.setPointer(unsafe.Pointer(&buf[0]))
+		return buf, nil
+	case []byte:
+		buf := make([]byte, len(v))
+		copy(buf, v)
+		c.size = uint32(len(buf))
+		if len(buf) > 0 {
+			c.setPointer(unsafe.Pointer(&buf[0]))
+		}
+		return buf, nil
+	case []uint16:
+		buf := make([]uint16, len(v))
+		copy(buf, v)
+		c.size = uint32(2 * len(buf))
+		if len(buf) > 0 {
+			c.setPointer(unsafe.Pointer(&buf[0]))
+		}
+		return buf, nil
+	case []uint32:
+		buf := make([]uint32, len(v))
+		copy(buf, v)
+		c.size = uint32(4 * len(buf))
+		if len(buf) > 0 {
+			c.setPointer(unsafe.Pointer(&buf[0]))
+		}
+		return buf, nil
+	default:
+		return nil, fmt.Errorf("Unsupported control value type %T", value)
+	}
+	return nil, nil
+}
+
+// Converts value returned by the driver to the type of the original value
+func decodeControlValue(c *v4l2_ext_control, value interface{}, payload interface{}) interface{} {
+	switch value.(type) {
+	case int32:
+		return int32(NativeByteOrder.Uint32(c.union[:]))
+	case bool:
+		return NativeByteOrder.Uint32(c.union[:]) != 0
+	case int64:
+		return int64(NativeByteOrder.Uint64(c.union[:]))
+	case string:
+		return CToGoString(payload.([]byte))
+	}
+	return payload
+}
@@ -0,0 +1,208 @@
+package webcam
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"golang.org/x/sys/unix"
+)
+
+// Starts streaming like StartStreaming, but if the device reports
+// that there is not enough bandwidth (ENOSPC), e.g. because several
+// cameras share a USB bus, steps down to a less demanding mode and retries.
+// Compressed formats are tried first if current format is uncompressed,
+// then modes with lower resolution or framerate, least degraded first.
+// Only modes satisfying given preferences are considered, preferred
+// interval and bandwidth options are ignored.
+// Returns the mode streaming was started with alongside with
+// an explanation of every step down. If streaming couldn't be started
+// in any mode, the original mode is restored and returned together
+// with the explanation and an error.
+// Preferences and the mode streaming was started with are kept
+// and used again if the stream is restarted after source change.
+func (w *Webcam) StartStreamingWithFallback(p FormatPreferences) (*NegotiatedFormat, error) {
+	result := &NegotiatedFormat{}
+
+	f, width, height, err := w.GetImageFormat()
+	if err != nil {
+		return nil, err
+	}
+	interval, _ := w.GetFrameInterval()
+	current := modeCandidate{format: f, width: width, height: height, interval: interval}
+
+	// Remembered to restart the same way after source change
+	w.preferences = &p
+
+	err = w.StartStreaming()
+	if !isBandwidthError(err) {
+		if err != nil {
+			return nil, err
+		}
+		if w.fallbackMode != nil && !w.fallbackMode.sameMode(current) {
+			w.fallbackMode = nil
+		}
+		result.setMode(current)
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%s started", current))
+		return result, nil
+	}
+	result.Reasons = append(result.Reasons, fmt.Sprintf("%s failed: %s", current, err))
+
+	for _, c := range w.fallbackCandidates(p, current, w.GetFormatDescriptions()) {
+		f, width, height, err := w.SetImageFormat(c.format, c.width, c.height)
+		if err != nil {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("%s rejected: %s", c, err))
+			continue
+		}
+		if f != c.format || width != c.width || height != c.height {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("%s rejected: driver proposed %s %dx%d", c, f, width, height))
+			continue
+		}
+		if c.interval.Denominator != 0 {
+			c.interval, err = w.SetFrameInterval(c.interval.Numerator, c.interval.Denominator)
+			if err != nil {
+				c.interval, _ = w.GetFrameInterval()
+			}
+		}
+
+		err = w.StartStreaming()
+		if isBandwidthError(err) {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("%s failed: %s", c, err))
+			continue
+		}
+		if err != nil {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("%s failed: %s", c, err))
+			w.restoreMode(result, current)
+			return result, err
+		}
+
+		w.fallbackMode = &c
+		result.setMode(c)
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%s started", c))
+		return result, nil
+	}
+
+	w.restoreMode(result, current)
+	return result, errors.New("Not enough bandwidth for any supported mode")
+}
+
+// Sets the mode that was active before fallback, failures are
+// only recorded in reasons as the original error is more relevant
+func (w *Webcam) restoreMode(result *NegotiatedFormat, mode modeCandidate) {
+	err := w.applyMode(mode)
+	if err != nil {
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%s not restored: %s", mode, err))
+		return
+	}
+	result.setMode(mode)
+	result.Reasons = append(result.Reasons, fmt.Sprintf("%s restored", mode))
+}
+
+// Sets format, frame size and frame interval of given mode.
+// Interval is kept if the device doesn't support setting it
+func (w *Webcam) applyMode(mode modeCandidate) error {
+	f, width, height, err := w.SetImageFormat(mode.format, mode.width, mode.height)
+	if err != nil {
+		return err
+	}
+	if f != mode.format || width != mode.width || height != mode.height {
+		return fmt.Errorf("Driver proposed %s %dx%d", f, width, height)
+	}
+	if mode.interval.Denominator != 0 {
+		_, err = w.SetFrameInterval(mode.interval.Numerator, mode.interval.Denominator)
+		if _, unsupported := err.(*FramerateUnsupported); unsupported {
+			err = nil
+		}
+	}
+	return err
+}
+
+func (r *NegotiatedFormat) setMode(c modeCandidate) {
+	r.Format = c.format
+	r.Width = c.width
+	r.Height = c.height
+	r.Interval = c.interval
+}
+
+func (c modeCandidate) sameMode(o modeCandidate) bool {
+	return c.format == o.format && c.width == o.width && c.height == o.height && c.interval == o.interval
+}
+
+func isBandwidthError(err error) bool {
+	se, ok := err.(*StreamingError)
+	return ok && se.Err == unix.ENOSPC
+}
+
+// Returns every mode less demanding than current one, least degraded first
+func (w *Webcam) fallbackCandidates(p FormatPreferences, current modeCandidate, supported []FormatDescription) []modeCandidate {
+	compressed := make(map[PixelFormat]bool)
+	formats := p.Formats
+	for _, d := range supported {
+		compressed[d.Format] = d.Compressed()
+		if len(p.Formats) == 0 {
+			formats = append(formats, d.Format)
+		}
+	}
+
+	rate := func(c modeCandidate) float64 {
+		if c.interval.Denominator == 0 {
+			c.interval = current.interval
+		}
+		return c.pixelRate()
+	}
+	currentRate := rate(current)
+	currentCompressed := compressed[current.format]
+
+	candidates := make([]modeCandidate, 0)
+	for rank, f := range formats {
+		isCompressed, ok := compressed[f]
+		if !ok {
+			continue
+		}
+		for _, size := range w.GetSupportedFrameSizes(f) {
+			for _, dim := range p.sizesWithin(size) {
+				intervals := w.GetSupportedFrameIntervals(f, dim[0], dim[1])
+				for _, interval := range fallbackIntervals(intervals) {
+					c := modeCandidate{format: f, rank: rank, width: dim[0], height: dim[1], interval: interval}
+					switch {
+					case c.sameMode(current):
+					case isCompressed && !currentCompressed && rate(c) <= currentRate:
+						candidates = append(candidates, c)
+					case rate(c) < currentRate:
+						candidates = append(candidates, c)
+					}
+				}
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if !currentCompressed && compressed[a.format] != compressed[b.format] {
+			return compressed[a.format]
+		}
+		if ra, rb := rate(a), rate(b); ra != rb {
+			return ra > rb
+		}
+		return a.rank < b.rank
+	})
+
+	return candidates
+}
+
+// Returns every discrete interval and bounds of stepwise ranges.
+// If device doesn't report intervals, zero interval is returned,
+// meaning that the current interval is kept
+func fallbackIntervals(intervals []FrameInterval) []Fraction {
+	result := make([]Fraction, 0, len(intervals))
+	for _, i := range intervals {
+		result = append(result, i.Min)
+		if i.Max != i.Min {
+			result = append(result, i.Max)
+		}
+	}
+	if len(result) == 0 {
+		result = append(result, Fraction{})
+	}
+	return result
+}
@@ -9,6 +9,44 @@ import "fmt"
 // of supported image formats
 type PixelFormat uint32
 
+// Image format supported by a webcam as reported by VIDIOC_ENUM_FMT.
+// Flags is a combination of V4L2_FMT_FLAG_* values
+type FormatDescription struct {
+	Format      PixelFormat
+	Description string
+	Flags       uint32
+}
+
+// Format is compressed, e.g. MJPEG or H.264
+func (d FormatDescription) Compressed() bool {
+	return (d.Flags & V4L2_FMT_FLAG_COMPRESSED) != 0
+}
+
+// Format is converted from a native one in software, e.g. by libv4l
+func (d FormatDescription) Emulated() bool {
+	return (d.Flags & V4L2_FMT_FLAG_EMULATED) != 0
+}
+
+// Layout of frames in the current image format.
+// BytesPerLine is the distance between the starts of two
+// consecutive lines, which can be larger than the line itself,
+// SizeImage is the size of the buffer holding a frame
+type FrameFormat struct {
+	Format       PixelFormat
+	Width        uint32
+	Height       uint32
+	BytesPerLine uint32
+	SizeImage    uint32
+
+	// Colorimetry reported by the driver, V4L2_COLORSPACE_*,
+	// V4L2_YCBCR_ENC_*, V4L2_QUANTIZATION_* and V4L2_XFER_FUNC_*
+	// values. Zero values mean defaults of the colorspace
+	Colorspace    uint32
+	YCbCrEncoding uint32
+	Quantization  uint32
+	XferFunc      uint32
+}
+
 // Struct that describes frame size supported by a webcam
 // For fixed sizes min and max values will be the same and
 // step value will be equal to '0'
@@ -32,3 +70,36 @@ func (s FrameSize) GetString() string {
 		return fmt.Sprintf("[%d-%d;%d]x[%d-%d;%d]", s.MinWidth, s.MaxWidth, s.StepWidth, s.MinHeight, s.MaxHeight, s.StepHeight)
 	}
 }
+
+// Fraction represents a rational number as used by V4L2,
+// e.g. a frame interval of 1/30 seconds
+type Fraction struct {
+	Numerator   uint32
+	Denominator uint32
+}
+
+// Returns string representation of a fraction, e.g. 1001/30000
+func (f Fraction) String() string {
+	return fmt.Sprintf("%d/%d", f.Numerator, f.Denominator)
+}
+
+// Struct that describes frame interval supported by a webcam
+// for a given image format and frame size.
+// For discrete intervals min and max values will be the same and
+// step value will be equal to '0/0'
+type FrameInterval struct {
+	Min  Fraction
+	Max  Fraction
+	Step Fraction
+}
+
+// Returns string representation of frame interval, e.g.
+// 1/30 for discrete intervals and
+// [1/60-1/5;1/60] for stepwise and continuous intervals
+func (i FrameInterval) GetString() string {
+	if i.Step.Numerator == 0 && i.Step.Denominator == 0 {
+		return i.Max.String()
+	} else {
+		return fmt.Sprintf("[%s-%s;%s]", i.Min, i.Max, i.Step)
+	}
+}
@@ -0,0 +1,364 @@
+package webcam
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// Defines what GetFrame does with damaged compressed frames
+type FrameValidation int
+
+const (
+	// Frames are passed on as is
+	FrameValidationNone FrameValidation = iota
+
+	// Damaged frames are returned together with CorruptFrame error
+	FrameValidationFlag
+
+	// Damaged frames are requeued and GetFrame returns empty slice
+	FrameValidationDrop
+)
+
+// Counters of the current streaming session, reset by StartStreaming
+type StreamStats struct {
+	// Frames dequeued from the driver
+	Frames uint64
+	// Frames found damaged by validation or marked with V4L2_BUF_FLAG_ERROR
+	Corrupt uint64
+	// Damaged frames dropped in FrameValidationDrop mode
+	Dropped uint64
+}
+
+// Set how frames are checked by GetFrame and ReadFrame.
+// MJPEG and JPEG frames are checked for SOI and EOI markers, segment
+// structure and frame size declared in SOF segment, H.264 frames
+// are checked for NAL unit structure and frame size declared in SPS.
+// Frames of all formats are checked for sizes reported by the driver
+// and for V4L2_BUF_FLAG_ERROR
+func (w *Webcam) SetFrameValidation(mode FrameValidation) {
+	w.frameValidation = mode
+}
+
+// Returns counters of the current streaming session
+func (w *Webcam) GetStreamStats() StreamStats {
+	return w.stats
+}
+
+// Checks frame according to validation mode.
+// Returns true if frame should be dropped
+func (w *Webcam) checkFrame(frame []byte, index, flags uint32) (bool, error) {
+	w.stats.Frames++
+	if w.frameValidation == FrameValidationNone {
+		return false, nil
+	}
+
+	reason := ""
+	switch {
+	case (flags & V4L2_BUF_FLAG_ERROR) != 0:
+		reason = "driver reported error"
+	case len(frame) == 0:
+		reason = "frame is empty"
+	case w.frameFormat.SizeImage != 0 && uint32(len(frame)) > w.frameFormat.SizeImage:
+		reason = fmt.Sprintf("frame size %d exceeds image size %d", len(frame), w.frameFormat.SizeImage)
+	default:
+		var err error
+		switch w.frameFormat.Format {
+		case V4L2_PIX_FMT_MJPEG, V4L2_PIX_FMT_JPEG:
+			err = checkJPEG(frame, w.frameFormat.Width, w.frameFormat.Height)
+		case V4L2_PIX_FMT_H264:
+			err = checkH264(frame, w.frameFormat.Width, w.frameFormat.Height)
+		}
+		if err != nil {
+			reason = err.Error()
+		}
+	}
+	if reason == "" {
+		return false, nil
+	}
+
+	w.stats.Corrupt++
+	if w.frameValidation == FrameValidationDrop {
+		w.stats.Dropped++
+		return true, nil
+	}
+	return false, &CorruptFrame{index, reason}
+}
+
+// Checks JPEG markers up to the first scan, frame size
+// declared in SOF segment and EOI marker at the end of the frame
+func checkJPEG(frame []byte, width, height uint32) error {
+	if len(frame) < 4 || frame[0] != 0xff || frame[1] != 0xd8 {
+		return errors.New("missing SOI marker")
+	}
+
+	// Some cameras pad frames with zeros
+	end := len(frame)
+	for end > 2 && frame[end-1] == 0 {
+		end--
+	}
+	if end < 4 || frame[end-2] != 0xff || frame[end-1] != 0xd9 {
+		return errors.New("missing EOI marker, frame is truncated")
+	}
+
+	pos := 2
+	for {
+		for pos+1 < end && frame[pos] == 0xff && frame[pos+1] == 0xff {
+			pos++
+		}
+		if pos+4 > end || frame[pos] != 0xff {
+			return errors.New("invalid segment structure")
+		}
+		marker := frame[pos+1]
+		length := int(binary.BigEndian.Uint16(frame[pos+2:]))
+		if length < 2 || pos+2+length > end {
+			return errors.New("segment is truncated")
+		}
+
+		switch {
+		case marker == 0xda:
+			// Entropy-coded data follows
+			return nil
+		case marker >= 0xc0 && marker <= 0xcf && marker != 0xc4 && marker != 0xc8 && marker != 0xcc:
+			// Start of frame
+			if length < 8 {
+				return errors.New("SOF segment is truncated")
+			}
+			h := uint32(binary.BigEndian.Uint16(frame[pos+5:]))
+			w := uint32(binary.BigEndian.Uint16(frame[pos+7:]))
+			if (width != 0 && w != width) || (height != 0 && h != height) {
+				return fmt.Errorf("frame size %dx%d doesn't match format %dx%d", w, h, width, height)
+			}
+		}
+		pos += 2 + length
+	}
+}
+
+// Checks NAL units of an H.264 byte stream
+// and frame size declared in SPS if present
+func checkH264(frame []byte, width, height uint32) error {
+	units := splitNALUnits(frame)
+	if len(units) == 0 {
+		return errors.New("missing start code")
+	}
+
+	for _, nal := range units {
+		if len(nal) == 0 {
+			return errors.New("empty NAL unit")
+		}
+		if nal[0]&0x80 != 0 {
+			return errors.New("forbidden bit is set in NAL unit header")
+		}
+		nalType := nal[0] & 0x1f
+		if nalType == 0 {
+			return errors.New("NAL unit of unspecified type")
+		}
+		if nalType == 7 {
+			w, h, err := spsFrameSize(nal[1:])
+			if err != nil {
+				return err
+			}
+			if (width != 0 && w != width) || (height != 0 && h != height) {
+				return fmt.Errorf("frame size %dx%d doesn't match format %dx%d", w, h, width, height)
+			}
+		}
+	}
+	return nil
+}
+
+// Splits Annex B byte stream to NAL units.
+// Returns nil if stream doesn't start with start code
+func splitNALUnits(data []byte) [][]byte {
+	start := nextStartCode(data, 0)
+	if start < 0 {
+		return nil
+	}
+	// Only zeros may precede the first start code
+	for _, b := range data[:start] {
+		if b != 0 {
+			return nil
+		}
+	}
+
+	units := make([][]byte, 0)
+	start += 3
+	for {
+		next := nextStartCode(data, start)
+		if next < 0 {
+			return append(units, data[start:])
+		}
+		end := next
+		// Zero byte of 4 byte start code and trailing zeros
+		for end > start && data[end-1] == 0 {
+			end--
+		}
+		units = append(units, data[start:end])
+		start = next + 3
+	}
+}
+
+// Returns position of the next 00 00 01 sequence
+func nextStartCode(data []byte, from int) int {
+	for i := from; i+2 < len(data); i++ {
+		if data[i] == 0 && data[i+1] == 0 && data[i+2] == 1 {
+			return i
+		}
+	}
+	return -1
+}
+
+// Reads frame size from sequence parameter set (without NAL header)
+func spsFrameSize(sps []byte) (width, height uint32, err error) {
+	r := &bitReader{data: unescapeRBSP(sps)}
+
+	profile := r.bits(8)
+	r.bits(16) // constraint flags and level
+	r.ue()     // seq_parameter_set_id
+
+	chromaFormat := uint32(1)
+	separateColourPlane := false
+	switch profile {
+	case 100, 110, 122, 244, 44, 83, 86, 118, 128, 138, 139, 134, 135:
+		chromaFormat = r.ue()
+		if chromaFormat == 3 {
+			separateColourPlane = r.bits(1) == 1
+		}
+		r.ue()    // bit_depth_luma_minus8
+		r.ue()    // bit_depth_chroma_minus8
+		r.bits(1) // qpprime_y_zero_transform_bypass_flag
+		if r.bits(1) == 1 {
+			lists := 8
+			if chromaFormat == 3 {
+				lists = 12
+			}
+			for i := 0; i < lists; i++ {
+				if r.bits(1) == 1 {
+					size := 16
+					if i >= 6 {
+						size = 64
+					}
+					r.skipScalingList(size)
+				}
+			}
+		}
+	}
+
+	r.ue() // log2_max_frame_num_minus4
+	switch r.ue() {
+	case 0:
+		r.ue() // log2_max_pic_order_cnt_lsb_minus4
+	case 1:
+		r.bits(1) // delta_pic_order_always_zero_flag
+		r.se()    // offset_for_non_ref_pic
+		r.se()    // offset_for_top_to_bottom_field
+		cycle := r.ue()
+		for i := uint32(0); i < cycle && r.err == nil; i++ {
+			r.se()
+		}
+	}
+	r.ue()    // max_num_ref_frames
+	r.bits(1) // gaps_in_frame_num_value_allowed_flag
+
+	widthMbs := r.ue() + 1
+	heightMapUnits := r.ue() + 1
+	frameMbsOnly := r.bits(1)
+	if frameMbsOnly == 0 {
+		r.bits(1) // mb_adaptive_frame_field_flag
+	}
+	r.bits(1) // direct_8x8_inference_flag
+
+	width = widthMbs * 16
+	height = (2 - frameMbsOnly) * heightMapUnits * 16
+
+	if r.bits(1) == 1 {
+		left, right, top, bottom := r.ue(), r.ue(), r.ue(), r.ue()
+		cropX, cropY := uint32(1), 2-frameMbsOnly
+		if !separateColourPlane && chromaFormat != 0 {
+			if chromaFormat != 3 {
+				cropX = 2
+			}
+			if chromaFormat == 1 {
+				cropY *= 2
+			}
+		}
+		width -= cropX * (left + right)
+		height -= cropY * (top + bottom)
+	}
+
+	if r.err != nil {
+		return 0, 0, errors.New("SPS is truncated")
+	}
+	return width, height, nil
+}
+
+// Removes emulation prevention bytes (00 00 03)
+func unescapeRBSP(data []byte) []byte {
+	result := make([]byte, 0, len(data))
+	zeros := 0
+	for _, b := range data {
+		if zeros >= 2 && b == 3 {
+			zeros = 0
+			continue
+		}
+		if b == 0 {
+			zeros++
+		} else {
+			zeros = 0
+		}
+		result = append(result, b)
+	}
+	return result
+}
+
+// Reads bits and Exp-Golomb codes of H.264 syntax elements
+type bitReader struct {
+	data []byte
+	pos  int
+	err  error
+}
+
+func (r *bitReader) bits(n int) uint32 {
+	var v uint32
+	for i := 0; i < n; i++ {
+		if r.pos >= len(r.data)*8 {
+			r.err = errors.New("out of data")
+			return 0
+		}
+		bit := (r.data[r.pos/8] >> uint(7-r.pos%8)) & 1
+		v = v<<1 | uint32(bit)
+		r.pos++
+	}
+	return v
+}
+
+func (r *bitReader) ue() uint32 {
+	zeros := 0
+	for r.bits(1) == 0 {
+		if r.err != nil || zeros == 31 {
+			r.err = errors.New("invalid Exp-Golomb code")
+			return 0
+		}
+		zeros++
+	}
+	return (1<<uint(zeros) - 1) + r.bits(zeros)
+}
+
+func (r *bitReader) se() int32 {
+	v := r.ue()
+	if v&1 == 1 {
+		return int32((v + 1) / 2)
+	}
+	return -int32(v / 2)
+}
+
+func (r *bitReader) skipScalingList(size int) {
+	last, next := int32(8), int32(8)
+	for j := 0; j < size && r.err == nil; j++ {
+		if next != 0 {
+			next = (last + r.se() + 256) % 256
+		}
+		if next != 0 {
+			last = next
+		}
+	}
+}
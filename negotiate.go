@@ -0,0 +1,298 @@
+package webcam
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Ranked preferences used by Negotiate to select an image format,
+// frame size and frame interval
+type FormatPreferences struct {
+	// Acceptable formats, most preferred first.
+	// Empty list means any format advertised by the device,
+	// in the order reported by the driver
+	Formats []PixelFormat
+
+	// Frame size limits, zero means no limit
+	MinWidth  uint32
+	MinHeight uint32
+	MaxWidth  uint32
+	MaxHeight uint32
+
+	// Preferred frame interval, e.g. 1/30 for 30 FPS.
+	// Zero value means the device default interval is kept
+	Interval Fraction
+
+	// By default the largest frame size is preferred.
+	// If set, the mode with the smallest pixel rate is preferred instead
+	MinimizeBandwidth bool
+}
+
+// Mode selected and applied by Negotiate
+type NegotiatedFormat struct {
+	Format   PixelFormat
+	Width    uint32
+	Height   uint32
+	Interval Fraction
+
+	// Human readable explanation of the choice,
+	// including the candidates that were rejected
+	Reasons []string
+}
+
+type modeCandidate struct {
+	format   PixelFormat
+	rank     int
+	width    uint32
+	height   uint32
+	interval Fraction
+}
+
+func (c modeCandidate) String() string {
+	s := fmt.Sprintf("%s %dx%d", c.format, c.width, c.height)
+	if c.interval.Denominator != 0 {
+		s += " @ " + c.interval.String()
+	}
+	return s
+}
+
+// Selects the best mode according to given preferences using formats,
+// frame sizes and frame intervals advertised by the device, checks it
+// with VIDIOC_TRY_FMT and applies it.
+// Candidates are ordered by format preference first, then by distance
+// to the preferred frame interval and then by frame size.
+// Preferences are kept and used again if the stream is restarted
+// after source change, see SubscribeStreamEvents.
+func (w *Webcam) Negotiate(p FormatPreferences) (*NegotiatedFormat, error) {
+	result := &NegotiatedFormat{}
+
+	candidates := w.modeCandidates(p)
+	if len(candidates) == 0 {
+		return nil, errors.New("No supported mode matches preferences")
+	}
+
+	for _, c := range candidates {
+		code := uint32(c.format)
+		width, height := c.width, c.height
+
+		err := tryImageFormat(w.fd, &code, &width, &height)
+		if err != nil {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("%s rejected: %s", c, err))
+			continue
+		}
+		if PixelFormat(code) != c.format || width != c.width || height != c.height {
+			result.Reasons = append(result.Reasons, fmt.Sprintf("%s rejected: driver proposed %s %dx%d", c, PixelFormat(code), width, height))
+			continue
+		}
+
+		f, width, height, err := w.SetImageFormat(c.format, c.width, c.height)
+		if err != nil {
+			return nil, err
+		}
+		result.Format = f
+		result.Width = width
+		result.Height = height
+		result.Reasons = append(result.Reasons, fmt.Sprintf("%s selected: %s", c, c.explain(p)))
+
+		if c.interval.Denominator != 0 {
+			interval, err := w.SetFrameInterval(c.interval.Numerator, c.interval.Denominator)
+			switch err.(type) {
+			case nil:
+				result.Interval = interval
+			case *FramerateUnsupported:
+				result.Reasons = append(result.Reasons, "Frame interval kept: "+err.Error())
+			default:
+				return nil, err
+			}
+		}
+		if result.Interval.Denominator == 0 {
+			result.Interval, _ = w.GetFrameInterval()
+		}
+
+		// Remembered to renegotiate after source change,
+		// the new mode replaces one chosen by fallback
+		w.preferences = &p
+		w.fallbackMode = nil
+
+		return result, nil
+	}
+
+	return nil, errors.New("No mode matching preferences was accepted by the driver")
+}
+
+func (c modeCandidate) explain(p FormatPreferences) string {
+	s := fmt.Sprintf("format preference %d", c.rank+1)
+	if p.Interval.Denominator != 0 {
+		s += ", closest interval to " + p.Interval.String()
+	}
+	if p.MinimizeBandwidth {
+		s += ", lowest bandwidth"
+	} else {
+		s += ", largest size"
+	}
+	return s
+}
+
+func (w *Webcam) modeCandidates(p FormatPreferences) []modeCandidate {
+	var formats []PixelFormat
+	supported := make(map[PixelFormat]bool)
+	for _, d := range w.GetFormatDescriptions() {
+		supported[d.Format] = true
+		if len(p.Formats) == 0 {
+			// Driver order is kept as preference order
+			formats = append(formats, d.Format)
+		}
+	}
+	if len(p.Formats) != 0 {
+		formats = p.Formats
+	}
+
+	candidates := make([]modeCandidate, 0)
+	for rank, f := range formats {
+		if !supported[f] {
+			continue
+		}
+		for _, size := range w.GetSupportedFrameSizes(f) {
+			for _, dim := range p.sizesWithin(size) {
+				c := modeCandidate{format: f, rank: rank, width: dim[0], height: dim[1]}
+				c.interval = p.closestInterval(w.GetSupportedFrameIntervals(f, c.width, c.height))
+				candidates = append(candidates, c)
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if da, db := p.intervalDistance(a.interval), p.intervalDistance(b.interval); da != db {
+			return da < db
+		}
+		if p.MinimizeBandwidth {
+			return a.pixelRate() < b.pixelRate()
+		}
+		return uint64(a.width)*uint64(a.height) > uint64(b.width)*uint64(b.height)
+	})
+
+	return candidates
+}
+
+// Returns frame sizes of s satisfying size limits.
+// Discrete sizes are returned as is, for stepwise sizes
+// the smallest and the largest acceptable sizes are returned
+func (p FormatPreferences) sizesWithin(s FrameSize) [][2]uint32 {
+	if s.StepWidth == 0 && s.StepHeight == 0 {
+		if p.acceptsSize(s.MaxWidth, s.MaxHeight) {
+			return [][2]uint32{{s.MaxWidth, s.MaxHeight}}
+		}
+		return nil
+	}
+
+	minW := stepUp(s.MinWidth, s.StepWidth, p.MinWidth)
+	minH := stepUp(s.MinHeight, s.StepHeight, p.MinHeight)
+	maxW := stepDown(s.MinWidth, s.StepWidth, s.MaxWidth, p.MaxWidth)
+	maxH := stepDown(s.MinHeight, s.StepHeight, s.MaxHeight, p.MaxHeight)
+
+	result := make([][2]uint32, 0, 2)
+	if p.acceptsSize(minW, minH) && minW <= s.MaxWidth && minH <= s.MaxHeight {
+		result = append(result, [2]uint32{minW, minH})
+	}
+	if p.acceptsSize(maxW, maxH) && (maxW != minW || maxH != minH) {
+		result = append(result, [2]uint32{maxW, maxH})
+	}
+	return result
+}
+
+func (p FormatPreferences) acceptsSize(width, height uint32) bool {
+	if width < p.MinWidth || height < p.MinHeight {
+		return false
+	}
+	if p.MaxWidth != 0 && width > p.MaxWidth {
+		return false
+	}
+	if p.MaxHeight != 0 && height > p.MaxHeight {
+		return false
+	}
+	return true
+}
+
+// Smallest value of min + n*step which is not less than limit
+func stepUp(min, step, limit uint32) uint32 {
+	if limit <= min {
+		return min
+	}
+	if step == 0 {
+		return limit
+	}
+	return min + (limit-min+step-1)/step*step
+}
+
+// Largest value of min + n*step which is not greater than max and limit
+func stepDown(min, step, max, limit uint32) uint32 {
+	if limit == 0 || limit > max {
+		limit = max
+	}
+	if limit <= min {
+		return min
+	}
+	if step == 0 {
+		return limit
+	}
+	return min + (limit-min)/step*step
+}
+
+// Picks the interval closest to the preferred one.
+// For stepwise and continuous ranges the preferred interval is used
+// when it lies within the range, otherwise the nearest bound is used
+func (p FormatPreferences) closestInterval(intervals []FrameInterval) Fraction {
+	if p.Interval.Denominator == 0 || len(intervals) == 0 {
+		return Fraction{}
+	}
+
+	var best Fraction
+	bestDistance := math.Inf(1)
+	for _, i := range intervals {
+		c := i.Max
+		if i.Step.Denominator != 0 {
+			v := fractionValue(p.Interval)
+			switch {
+			case v < fractionValue(i.Min):
+				c = i.Min
+			case v > fractionValue(i.Max):
+				c = i.Max
+			default:
+				c = p.Interval
+			}
+		}
+		if d := p.intervalDistance(c); d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+	return best
+}
+
+// Distance between the preferred and given framerates in FPS
+func (p FormatPreferences) intervalDistance(f Fraction) float64 {
+	if p.Interval.Denominator == 0 {
+		return 0
+	}
+	if f.Numerator == 0 || f.Denominator == 0 {
+		return math.Inf(1)
+	}
+	return math.Abs(1/fractionValue(f) - 1/fractionValue(p.Interval))
+}
+
+func (c modeCandidate) pixelRate() float64 {
+	rate := float64(c.width) * float64(c.height)
+	if c.interval.Numerator != 0 {
+		rate /= fractionValue(c.interval)
+	}
+	return rate
+}
+
+func fractionValue(f Fraction) float64 {
+	return float64(f.Numerator) / float64(f.Denominator)
+}
@@ -0,0 +1,655 @@
+package webcam
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Set in big-endian variants of formats which also exist in little-endian
+const V4L2_PIX_FMT_FLAG_BE PixelFormat = 1 << 31
+
+// Image format codes defined in videodev2.h.
+// Code is a little-endian fourcc, e.g. 'Y' | 'U'<<8 | 'Y'<<16 | 'V'<<24.
+// The list follows videodev2.h of Linux 6.1, plus Y12P and Y14P
+// added in 6.3 and AV1_FRAME added in 6.5
+const (
+	// RGB formats (1 or 2 bytes per pixel)
+	V4L2_PIX_FMT_RGB332   PixelFormat = 'R' | 'G'<<8 | 'B'<<16 | '1'<<24                        // 8 RGB-3-3-2
+	V4L2_PIX_FMT_RGB444   PixelFormat = 'R' | '4'<<8 | '4'<<16 | '4'<<24                        // 16 xxxxrrrr ggggbbbb
+	V4L2_PIX_FMT_ARGB444  PixelFormat = 'A' | 'R'<<8 | '1'<<16 | '2'<<24                        // 16 aaaarrrr ggggbbbb
+	V4L2_PIX_FMT_XRGB444  PixelFormat = 'X' | 'R'<<8 | '1'<<16 | '2'<<24                        // 16 xxxxrrrr ggggbbbb
+	V4L2_PIX_FMT_RGBA444  PixelFormat = 'R' | 'A'<<8 | '1'<<16 | '2'<<24                        // 16 rrrrgggg bbbbaaaa
+	V4L2_PIX_FMT_RGBX444  PixelFormat = 'R' | 'X'<<8 | '1'<<16 | '2'<<24                        // 16 rrrrgggg bbbbxxxx
+	V4L2_PIX_FMT_ABGR444  PixelFormat = 'A' | 'B'<<8 | '1'<<16 | '2'<<24                        // 16 aaaabbbb ggggrrrr
+	V4L2_PIX_FMT_XBGR444  PixelFormat = 'X' | 'B'<<8 | '1'<<16 | '2'<<24                        // 16 xxxxbbbb ggggrrrr
+	V4L2_PIX_FMT_BGRA444  PixelFormat = 'G' | 'A'<<8 | '1'<<16 | '2'<<24                        // 16 bbbbgggg rrrraaaa
+	V4L2_PIX_FMT_BGRX444  PixelFormat = 'B' | 'X'<<8 | '1'<<16 | '2'<<24                        // 16 bbbbgggg rrrrxxxx
+	V4L2_PIX_FMT_RGB555   PixelFormat = 'R' | 'G'<<8 | 'B'<<16 | 'O'<<24                        // 16 RGB-5-5-5
+	V4L2_PIX_FMT_ARGB555  PixelFormat = 'A' | 'R'<<8 | '1'<<16 | '5'<<24                        // 16 ARGB-1-5-5-5
+	V4L2_PIX_FMT_XRGB555  PixelFormat = 'X' | 'R'<<8 | '1'<<16 | '5'<<24                        // 16 XRGB-1-5-5-5
+	V4L2_PIX_FMT_RGBA555  PixelFormat = 'R' | 'A'<<8 | '1'<<16 | '5'<<24                        // 16 RGBA-5-5-5-1
+	V4L2_PIX_FMT_RGBX555  PixelFormat = 'R' | 'X'<<8 | '1'<<16 | '5'<<24                        // 16 RGBX-5-5-5-1
+	V4L2_PIX_FMT_ABGR555  PixelFormat = 'A' | 'B'<<8 | '1'<<16 | '5'<<24                        // 16 ABGR-1-5-5-5
+	V4L2_PIX_FMT_XBGR555  PixelFormat = 'X' | 'B'<<8 | '1'<<16 | '5'<<24                        // 16 XBGR-1-5-5-5
+	V4L2_PIX_FMT_BGRA555  PixelFormat = 'B' | 'A'<<8 | '1'<<16 | '5'<<24                        // 16 BGRA-5-5-5-1
+	V4L2_PIX_FMT_BGRX555  PixelFormat = 'B' | 'X'<<8 | '1'<<16 | '5'<<24                        // 16 BGRX-5-5-5-1
+	V4L2_PIX_FMT_RGB565   PixelFormat = 'R' | 'G'<<8 | 'B'<<16 | 'P'<<24                        // 16 RGB-5-6-5
+	V4L2_PIX_FMT_RGB555X  PixelFormat = 'R' | 'G'<<8 | 'B'<<16 | 'Q'<<24                        // 16 RGB-5-5-5 BE
+	V4L2_PIX_FMT_ARGB555X PixelFormat = 'A' | 'R'<<8 | '1'<<16 | '5'<<24 | V4L2_PIX_FMT_FLAG_BE // 16 ARGB-5-5-5 BE
+	V4L2_PIX_FMT_XRGB555X PixelFormat = 'X' | 'R'<<8 | '1'<<16 | '5'<<24 | V4L2_PIX_FMT_FLAG_BE // 16 XRGB-5-5-5 BE
+	V4L2_PIX_FMT_RGB565X  PixelFormat = 'R' | 'G'<<8 | 'B'<<16 | 'R'<<24                        // 16 RGB-5-6-5 BE
+
+	// RGB formats (3 or 4 bytes per pixel)
+	V4L2_PIX_FMT_BGR666 PixelFormat = 'B' | 'G'<<8 | 'R'<<16 | 'H'<<24 // 18 BGR-6-6-6
+	V4L2_PIX_FMT_BGR24  PixelFormat = 'B' | 'G'<<8 | 'R'<<16 | '3'<<24 // 24 BGR-8-8-8
+	V4L2_PIX_FMT_RGB24  PixelFormat = 'R' | 'G'<<8 | 'B'<<16 | '3'<<24 // 24 RGB-8-8-8
+	V4L2_PIX_FMT_BGR32  PixelFormat = 'B' | 'G'<<8 | 'R'<<16 | '4'<<24 // 32 BGR-8-8-8-8
+	V4L2_PIX_FMT_ABGR32 PixelFormat = 'A' | 'R'<<8 | '2'<<16 | '4'<<24 // 32 BGRA-8-8-8-8
+	V4L2_PIX_FMT_XBGR32 PixelFormat = 'X' | 'R'<<8 | '2'<<16 | '4'<<24 // 32 BGRX-8-8-8-8
+	V4L2_PIX_FMT_BGRA32 PixelFormat = 'R' | 'A'<<8 | '2'<<16 | '4'<<24 // 32 ABGR-8-8-8-8
+	V4L2_PIX_FMT_BGRX32 PixelFormat = 'R' | 'X'<<8 | '2'<<16 | '4'<<24 // 32 XBGR-8-8-8-8
+	V4L2_PIX_FMT_RGB32  PixelFormat = 'R' | 'G'<<8 | 'B'<<16 | '4'<<24 // 32 RGB-8-8-8-8
+	V4L2_PIX_FMT_RGBA32 PixelFormat = 'A' | 'B'<<8 | '2'<<16 | '4'<<24 // 32 RGBA-8-8-8-8
+	V4L2_PIX_FMT_RGBX32 PixelFormat = 'X' | 'B'<<8 | '2'<<16 | '4'<<24 // 32 RGBX-8-8-8-8
+	V4L2_PIX_FMT_ARGB32 PixelFormat = 'B' | 'A'<<8 | '2'<<16 | '4'<<24 // 32 ARGB-8-8-8-8
+	V4L2_PIX_FMT_XRGB32 PixelFormat = 'B' | 'X'<<8 | '2'<<16 | '4'<<24 // 32 XRGB-8-8-8-8
+
+	// Grey formats
+	V4L2_PIX_FMT_GREY   PixelFormat = 'G' | 'R'<<8 | 'E'<<16 | 'Y'<<24                        // 8 Greyscale
+	V4L2_PIX_FMT_Y4     PixelFormat = 'Y' | '0'<<8 | '4'<<16 | ' '<<24                        // 4 Greyscale
+	V4L2_PIX_FMT_Y6     PixelFormat = 'Y' | '0'<<8 | '6'<<16 | ' '<<24                        // 6 Greyscale
+	V4L2_PIX_FMT_Y10    PixelFormat = 'Y' | '1'<<8 | '0'<<16 | ' '<<24                        // 10 Greyscale
+	V4L2_PIX_FMT_Y12    PixelFormat = 'Y' | '1'<<8 | '2'<<16 | ' '<<24                        // 12 Greyscale
+	V4L2_PIX_FMT_Y14    PixelFormat = 'Y' | '1'<<8 | '4'<<16 | ' '<<24                        // 14 Greyscale
+	V4L2_PIX_FMT_Y16    PixelFormat = 'Y' | '1'<<8 | '6'<<16 | ' '<<24                        // 16 Greyscale
+	V4L2_PIX_FMT_Y16_BE PixelFormat = 'Y' | '1'<<8 | '6'<<16 | ' '<<24 | V4L2_PIX_FMT_FLAG_BE // 16 Greyscale BE
+
+	// Grey bit-packed formats
+	V4L2_PIX_FMT_Y10BPACK PixelFormat = 'Y' | '1'<<8 | '0'<<16 | 'B'<<24 // 10 Greyscale bit-packed
+	V4L2_PIX_FMT_Y10P     PixelFormat = 'Y' | '1'<<8 | '0'<<16 | 'P'<<24 // 10 Greyscale, MIPI RAW10 packed
+	V4L2_PIX_FMT_Y12P     PixelFormat = 'Y' | '1'<<8 | '2'<<16 | 'P'<<24 // 12 Greyscale, MIPI RAW12 packed
+	V4L2_PIX_FMT_Y14P     PixelFormat = 'Y' | '1'<<8 | '4'<<16 | 'P'<<24 // 14 Greyscale, MIPI RAW14 packed
+	V4L2_PIX_FMT_IPU3_Y10 PixelFormat = 'i' | 'p'<<8 | '3'<<16 | 'y'<<24 // IPU3 packed 10-bit greyscale
+
+	// Palette formats
+	V4L2_PIX_FMT_PAL8 PixelFormat = 'P' | 'A'<<8 | 'L'<<16 | '8'<<24 // 8 8-bit palette
+
+	// Chrominance formats
+	V4L2_PIX_FMT_UV8 PixelFormat = 'U' | 'V'<<8 | '8'<<16 | ' '<<24 // 8 UV 4:4
+
+	// Luminance+Chrominance formats
+	V4L2_PIX_FMT_YUYV   PixelFormat = 'Y' | 'U'<<8 | 'Y'<<16 | 'V'<<24 // 16 YUV 4:2:2
+	V4L2_PIX_FMT_YYUV   PixelFormat = 'Y' | 'Y'<<8 | 'U'<<16 | 'V'<<24 // 16 YUV 4:2:2
+	V4L2_PIX_FMT_YVYU   PixelFormat = 'Y' | 'V'<<8 | 'Y'<<16 | 'U'<<24 // 16 YVU 4:2:2
+	V4L2_PIX_FMT_UYVY   PixelFormat = 'U' | 'Y'<<8 | 'V'<<16 | 'Y'<<24 // 16 YUV 4:2:2
+	V4L2_PIX_FMT_VYUY   PixelFormat = 'V' | 'Y'<<8 | 'U'<<16 | 'Y'<<24 // 16 YUV 4:2:2
+	V4L2_PIX_FMT_Y41P   PixelFormat = 'Y' | '4'<<8 | '1'<<16 | 'P'<<24 // 12 YUV 4:1:1
+	V4L2_PIX_FMT_YUV444 PixelFormat = 'Y' | '4'<<8 | '4'<<16 | '4'<<24 // 16 xxxxyyyy uuuuvvvv
+	V4L2_PIX_FMT_YUV555 PixelFormat = 'Y' | 'U'<<8 | 'V'<<16 | 'O'<<24 // 16 YUV-5-5-5
+	V4L2_PIX_FMT_YUV565 PixelFormat = 'Y' | 'U'<<8 | 'V'<<16 | 'P'<<24 // 16 YUV-5-6-5
+	V4L2_PIX_FMT_YUV24  PixelFormat = 'Y' | 'U'<<8 | 'V'<<16 | '3'<<24 // 24 YUV-8-8-8
+	V4L2_PIX_FMT_YUV32  PixelFormat = 'Y' | 'U'<<8 | 'V'<<16 | '4'<<24 // 32 YUV-8-8-8-8
+	V4L2_PIX_FMT_AYUV32 PixelFormat = 'A' | 'Y'<<8 | 'U'<<16 | 'V'<<24 // 32 AYUV-8-8-8-8
+	V4L2_PIX_FMT_XYUV32 PixelFormat = 'X' | 'Y'<<8 | 'U'<<16 | 'V'<<24 // 32 XYUV-8-8-8-8
+	V4L2_PIX_FMT_VUYA32 PixelFormat = 'V' | 'U'<<8 | 'Y'<<16 | 'A'<<24 // 32 VUYA-8-8-8-8
+	V4L2_PIX_FMT_VUYX32 PixelFormat = 'V' | 'U'<<8 | 'Y'<<16 | 'X'<<24 // 32 VUYX-8-8-8-8
+	V4L2_PIX_FMT_YUVA32 PixelFormat = 'Y' | 'U'<<8 | 'V'<<16 | 'A'<<24 // 32 YUVA-8-8-8-8
+	V4L2_PIX_FMT_YUVX32 PixelFormat = 'Y' | 'U'<<8 | 'V'<<16 | 'X'<<24 // 32 YUVX-8-8-8-8
+	V4L2_PIX_FMT_M420   PixelFormat = 'M' | '4'<<8 | '2'<<16 | '0'<<24 // 12 YUV 4:2:0 2 lines y, 1 line uv interleaved
+
+	// two planes -- one Y, one Cr + Cb interleaved
+	V4L2_PIX_FMT_NV12 PixelFormat = 'N' | 'V'<<8 | '1'<<16 | '2'<<24 // 12 Y/CbCr 4:2:0
+	V4L2_PIX_FMT_NV21 PixelFormat = 'N' | 'V'<<8 | '2'<<16 | '1'<<24 // 12 Y/CrCb 4:2:0
+	V4L2_PIX_FMT_NV16 PixelFormat = 'N' | 'V'<<8 | '1'<<16 | '6'<<24 // 16 Y/CbCr 4:2:2
+	V4L2_PIX_FMT_NV61 PixelFormat = 'N' | 'V'<<8 | '6'<<16 | '1'<<24 // 16 Y/CrCb 4:2:2
+	V4L2_PIX_FMT_NV24 PixelFormat = 'N' | 'V'<<8 | '2'<<16 | '4'<<24 // 24 Y/CbCr 4:4:4
+	V4L2_PIX_FMT_NV42 PixelFormat = 'N' | 'V'<<8 | '4'<<16 | '2'<<24 // 24 Y/CrCb 4:4:4
+	V4L2_PIX_FMT_P010 PixelFormat = 'P' | '0'<<8 | '1'<<16 | '0'<<24 // 24 Y/CbCr 4:2:0 10-bit per component
+
+	// two non contiguous planes - one Y, one Cr + Cb interleaved
+	V4L2_PIX_FMT_NV12M PixelFormat = 'N' | 'M'<<8 | '1'<<16 | '2'<<24 // 12 Y/CbCr 4:2:0
+	V4L2_PIX_FMT_NV21M PixelFormat = 'N' | 'M'<<8 | '2'<<16 | '1'<<24 // 21 Y/CrCb 4:2:0
+	V4L2_PIX_FMT_NV16M PixelFormat = 'N' | 'M'<<8 | '1'<<16 | '6'<<24 // 16 Y/CbCr 4:2:2
+	V4L2_PIX_FMT_NV61M PixelFormat = 'N' | 'M'<<8 | '6'<<16 | '1'<<24 // 16 Y/CrCb 4:2:2
+
+	// three planes - Y Cb, Cr
+	V4L2_PIX_FMT_YUV410  PixelFormat = 'Y' | 'U'<<8 | 'V'<<16 | '9'<<24 // 9 YUV 4:1:0
+	V4L2_PIX_FMT_YVU410  PixelFormat = 'Y' | 'V'<<8 | 'U'<<16 | '9'<<24 // 9 YVU 4:1:0
+	V4L2_PIX_FMT_YUV411P PixelFormat = '4' | '1'<<8 | '1'<<16 | 'P'<<24 // 12 YVU411 planar
+	V4L2_PIX_FMT_YUV420  PixelFormat = 'Y' | 'U'<<8 | '1'<<16 | '2'<<24 // 12 YUV 4:2:0
+	V4L2_PIX_FMT_YVU420  PixelFormat = 'Y' | 'V'<<8 | '1'<<16 | '2'<<24 // 12 YVU 4:2:0
+	V4L2_PIX_FMT_YUV422P PixelFormat = '4' | '2'<<8 | '2'<<16 | 'P'<<24 // 16 YVU422 planar
+
+	// three non contiguous planes - Y, Cb, Cr
+	V4L2_PIX_FMT_YUV420M PixelFormat = 'Y' | 'M'<<8 | '1'<<16 | '2'<<24 // 12 YUV420 planar
+	V4L2_PIX_FMT_YVU420M PixelFormat = 'Y' | 'M'<<8 | '2'<<16 | '1'<<24 // 12 YVU420 planar
+	V4L2_PIX_FMT_YUV422M PixelFormat = 'Y' | 'M'<<8 | '1'<<16 | '6'<<24 // 16 YUV422 planar
+	V4L2_PIX_FMT_YVU422M PixelFormat = 'Y' | 'M'<<8 | '6'<<16 | '1'<<24 // 16 YVU422 planar
+	V4L2_PIX_FMT_YUV444M PixelFormat = 'Y' | 'M'<<8 | '2'<<16 | '4'<<24 // 24 YUV444 planar
+	V4L2_PIX_FMT_YVU444M PixelFormat = 'Y' | 'M'<<8 | '4'<<16 | '2'<<24 // 24 YVU444 planar
+
+	// Tiled YUV formats
+	V4L2_PIX_FMT_NV12_4L4   PixelFormat = 'V' | 'T'<<8 | '1'<<16 | '2'<<24 // 12 Y/CbCr 4:2:0 4x4 tiles
+	V4L2_PIX_FMT_NV12_16L16 PixelFormat = 'H' | 'M'<<8 | '1'<<16 | '2'<<24 // 12 Y/CbCr 4:2:0 16x16 tiles
+	V4L2_PIX_FMT_NV12_32L32 PixelFormat = 'S' | 'T'<<8 | '1'<<16 | '2'<<24 // 12 Y/CbCr 4:2:0 32x32 tiles
+	V4L2_PIX_FMT_P010_4L4   PixelFormat = 'T' | '0'<<8 | '1'<<16 | '0'<<24 // 12 Y/CbCr 4:2:0 10-bit 4x4 macroblocks
+
+	// Tiled YUV formats, non contiguous planes
+	V4L2_PIX_FMT_NV12MT           PixelFormat = 'T' | 'M'<<8 | '1'<<16 | '2'<<24                        // 12 Y/CbCr 4:2:0 64x32 tiles
+	V4L2_PIX_FMT_NV12MT_16X16     PixelFormat = 'V' | 'M'<<8 | '1'<<16 | '2'<<24                        // 12 Y/CbCr 4:2:0 16x16 tiles
+	V4L2_PIX_FMT_NV12M_8L128      PixelFormat = 'N' | 'A'<<8 | '1'<<16 | '2'<<24                        // Y/CbCr 4:2:0 8x128 tiles
+	V4L2_PIX_FMT_NV12M_10BE_8L128 PixelFormat = 'N' | 'T'<<8 | '1'<<16 | '2'<<24 | V4L2_PIX_FMT_FLAG_BE // Y/CbCr 4:2:0 10-bit 8x128 tiles
+
+	// Bayer formats - see http://www.siliconimaging.com/RGB%20Bayer.htm
+	V4L2_PIX_FMT_SBGGR8  PixelFormat = 'B' | 'A'<<8 | '8'<<16 | '1'<<24 // 8 BGBG.. GRGR..
+	V4L2_PIX_FMT_SGBRG8  PixelFormat = 'G' | 'B'<<8 | 'R'<<16 | 'G'<<24 // 8 GBGB.. RGRG..
+	V4L2_PIX_FMT_SGRBG8  PixelFormat = 'G' | 'R'<<8 | 'B'<<16 | 'G'<<24 // 8 GRGR.. BGBG..
+	V4L2_PIX_FMT_SRGGB8  PixelFormat = 'R' | 'G'<<8 | 'G'<<16 | 'B'<<24 // 8 RGRG.. GBGB..
+	V4L2_PIX_FMT_SBGGR10 PixelFormat = 'B' | 'G'<<8 | '1'<<16 | '0'<<24 // 10 BGBG.. GRGR..
+	V4L2_PIX_FMT_SGBRG10 PixelFormat = 'G' | 'B'<<8 | '1'<<16 | '0'<<24 // 10 GBGB.. RGRG..
+	V4L2_PIX_FMT_SGRBG10 PixelFormat = 'B' | 'A'<<8 | '1'<<16 | '0'<<24 // 10 GRGR.. BGBG..
+	V4L2_PIX_FMT_SRGGB10 PixelFormat = 'R' | 'G'<<8 | '1'<<16 | '0'<<24 // 10 RGRG.. GBGB..
+	// 10bit raw bayer packed, 5 bytes for every 4 pixels
+	V4L2_PIX_FMT_SBGGR10P PixelFormat = 'p' | 'B'<<8 | 'A'<<16 | 'A'<<24
+	V4L2_PIX_FMT_SGBRG10P PixelFormat = 'p' | 'G'<<8 | 'A'<<16 | 'A'<<24
+	V4L2_PIX_FMT_SGRBG10P PixelFormat = 'p' | 'g'<<8 | 'A'<<16 | 'A'<<24
+	V4L2_PIX_FMT_SRGGB10P PixelFormat = 'p' | 'R'<<8 | 'A'<<16 | 'A'<<24
+	// 10bit raw bayer a-law compressed to 8 bits
+	V4L2_PIX_FMT_SBGGR10ALAW8 PixelFormat = 'a' | 'B'<<8 | 'A'<<16 | '8'<<24
+	V4L2_PIX_FMT_SGBRG10ALAW8 PixelFormat = 'a' | 'G'<<8 | 'A'<<16 | '8'<<24
+	V4L2_PIX_FMT_SGRBG10ALAW8 PixelFormat = 'a' | 'g'<<8 | 'A'<<16 | '8'<<24
+	V4L2_PIX_FMT_SRGGB10ALAW8 PixelFormat = 'a' | 'R'<<8 | 'A'<<16 | '8'<<24
+	// 10bit raw bayer DPCM compressed to 8 bits
+	V4L2_PIX_FMT_SBGGR10DPCM8 PixelFormat = 'b' | 'B'<<8 | 'A'<<16 | '8'<<24
+	V4L2_PIX_FMT_SGBRG10DPCM8 PixelFormat = 'b' | 'G'<<8 | 'A'<<16 | '8'<<24
+	V4L2_PIX_FMT_SGRBG10DPCM8 PixelFormat = 'B' | 'D'<<8 | '1'<<16 | '0'<<24
+	V4L2_PIX_FMT_SRGGB10DPCM8 PixelFormat = 'b' | 'R'<<8 | 'A'<<16 | '8'<<24
+	V4L2_PIX_FMT_SBGGR12      PixelFormat = 'B' | 'G'<<8 | '1'<<16 | '2'<<24 // 12 BGBG.. GRGR..
+	V4L2_PIX_FMT_SGBRG12      PixelFormat = 'G' | 'B'<<8 | '1'<<16 | '2'<<24 // 12 GBGB.. RGRG..
+	V4L2_PIX_FMT_SGRBG12      PixelFormat = 'B' | 'A'<<8 | '1'<<16 | '2'<<24 // 12 GRGR.. BGBG..
+	V4L2_PIX_FMT_SRGGB12      PixelFormat = 'R' | 'G'<<8 | '1'<<16 | '2'<<24 // 12 RGRG.. GBGB..
+	// 12bit raw bayer packed, 6 bytes for every 4 pixels
+	V4L2_PIX_FMT_SBGGR12P PixelFormat = 'p' | 'B'<<8 | 'C'<<16 | 'C'<<24
+	V4L2_PIX_FMT_SGBRG12P PixelFormat = 'p' | 'G'<<8 | 'C'<<16 | 'C'<<24
+	V4L2_PIX_FMT_SGRBG12P PixelFormat = 'p' | 'g'<<8 | 'C'<<16 | 'C'<<24
+	V4L2_PIX_FMT_SRGGB12P PixelFormat = 'p' | 'R'<<8 | 'C'<<16 | 'C'<<24
+	V4L2_PIX_FMT_SBGGR14  PixelFormat = 'B' | 'G'<<8 | '1'<<16 | '4'<<24 // 14 BGBG.. GRGR..
+	V4L2_PIX_FMT_SGBRG14  PixelFormat = 'G' | 'B'<<8 | '1'<<16 | '4'<<24 // 14 GBGB.. RGRG..
+	V4L2_PIX_FMT_SGRBG14  PixelFormat = 'G' | 'R'<<8 | '1'<<16 | '4'<<24 // 14 GRGR.. BGBG..
+	V4L2_PIX_FMT_SRGGB14  PixelFormat = 'R' | 'G'<<8 | '1'<<16 | '4'<<24 // 14 RGRG.. GBGB..
+	// 14bit raw bayer packed, 7 bytes for every 4 pixels
+	V4L2_PIX_FMT_SBGGR14P PixelFormat = 'p' | 'B'<<8 | 'E'<<16 | 'E'<<24
+	V4L2_PIX_FMT_SGBRG14P PixelFormat = 'p' | 'G'<<8 | 'E'<<16 | 'E'<<24
+	V4L2_PIX_FMT_SGRBG14P PixelFormat = 'p' | 'g'<<8 | 'E'<<16 | 'E'<<24
+	V4L2_PIX_FMT_SRGGB14P PixelFormat = 'p' | 'R'<<8 | 'E'<<16 | 'E'<<24
+	V4L2_PIX_FMT_SBGGR16  PixelFormat = 'B' | 'Y'<<8 | 'R'<<16 | '2'<<24 // 16 BGBG.. GRGR..
+	V4L2_PIX_FMT_SGBRG16  PixelFormat = 'G' | 'B'<<8 | '1'<<16 | '6'<<24 // 16 GBGB.. RGRG..
+	V4L2_PIX_FMT_SGRBG16  PixelFormat = 'G' | 'R'<<8 | '1'<<16 | '6'<<24 // 16 GRGR.. BGBG..
+	V4L2_PIX_FMT_SRGGB16  PixelFormat = 'R' | 'G'<<8 | '1'<<16 | '6'<<24 // 16 RGRG.. GBGB..
+
+	// HSV formats
+	V4L2_PIX_FMT_HSV24 PixelFormat = 'H' | 'S'<<8 | 'V'<<16 | '3'<<24
+	V4L2_PIX_FMT_HSV32 PixelFormat = 'H' | 'S'<<8 | 'V'<<16 | '4'<<24
+
+	// compressed formats
+	V4L2_PIX_FMT_MJPEG          PixelFormat = 'M' | 'J'<<8 | 'P'<<16 | 'G'<<24 // Motion-JPEG
+	V4L2_PIX_FMT_JPEG           PixelFormat = 'J' | 'P'<<8 | 'E'<<16 | 'G'<<24 // JFIF JPEG
+	V4L2_PIX_FMT_DV             PixelFormat = 'd' | 'v'<<8 | 's'<<16 | 'd'<<24 // 1394
+	V4L2_PIX_FMT_MPEG           PixelFormat = 'M' | 'P'<<8 | 'E'<<16 | 'G'<<24 // MPEG-1/2/4 Multiplexed
+	V4L2_PIX_FMT_H264           PixelFormat = 'H' | '2'<<8 | '6'<<16 | '4'<<24 // H264 with start codes
+	V4L2_PIX_FMT_H264_NO_SC     PixelFormat = 'A' | 'V'<<8 | 'C'<<16 | '1'<<24 // H264 without start codes
+	V4L2_PIX_FMT_H264_MVC       PixelFormat = 'M' | '2'<<8 | '6'<<16 | '4'<<24 // H264 MVC
+	V4L2_PIX_FMT_H263           PixelFormat = 'H' | '2'<<8 | '6'<<16 | '3'<<24 // H263
+	V4L2_PIX_FMT_MPEG1          PixelFormat = 'M' | 'P'<<8 | 'G'<<16 | '1'<<24 // MPEG-1 ES
+	V4L2_PIX_FMT_MPEG2          PixelFormat = 'M' | 'P'<<8 | 'G'<<16 | '2'<<24 // MPEG-2 ES
+	V4L2_PIX_FMT_MPEG2_SLICE    PixelFormat = 'M' | 'G'<<8 | '2'<<16 | 'S'<<24 // MPEG-2 parsed slice data
+	V4L2_PIX_FMT_MPEG4          PixelFormat = 'M' | 'P'<<8 | 'G'<<16 | '4'<<24 // MPEG-4 part 2 ES
+	V4L2_PIX_FMT_XVID           PixelFormat = 'X' | 'V'<<8 | 'I'<<16 | 'D'<<24 // Xvid
+	V4L2_PIX_FMT_VC1_ANNEX_G    PixelFormat = 'V' | 'C'<<8 | '1'<<16 | 'G'<<24 // SMPTE 421M Annex G compliant stream
+	V4L2_PIX_FMT_VC1_ANNEX_L    PixelFormat = 'V' | 'C'<<8 | '1'<<16 | 'L'<<24 // SMPTE 421M Annex L compliant stream
+	V4L2_PIX_FMT_VP8            PixelFormat = 'V' | 'P'<<8 | '8'<<16 | '0'<<24 // VP8
+	V4L2_PIX_FMT_VP8_FRAME      PixelFormat = 'V' | 'P'<<8 | '8'<<16 | 'F'<<24 // VP8 parsed frame
+	V4L2_PIX_FMT_VP9            PixelFormat = 'V' | 'P'<<8 | '9'<<16 | '0'<<24 // VP9
+	V4L2_PIX_FMT_VP9_FRAME      PixelFormat = 'V' | 'P'<<8 | '9'<<16 | 'F'<<24 // VP9 parsed frame
+	V4L2_PIX_FMT_HEVC           PixelFormat = 'H' | 'E'<<8 | 'V'<<16 | 'C'<<24 // HEVC aka H.265
+	V4L2_PIX_FMT_FWHT           PixelFormat = 'F' | 'W'<<8 | 'H'<<16 | 'T'<<24 // Fast Walsh Hadamard Transform (vicodec)
+	V4L2_PIX_FMT_FWHT_STATELESS PixelFormat = 'S' | 'F'<<8 | 'W'<<16 | 'H'<<24 // Stateless FWHT (vicodec)
+	V4L2_PIX_FMT_H264_SLICE     PixelFormat = 'S' | '2'<<8 | '6'<<16 | '4'<<24 // H264 parsed slices
+	V4L2_PIX_FMT_HEVC_SLICE     PixelFormat = 'S' | '2'<<8 | '6'<<16 | '5'<<24 // HEVC parsed slices
+	V4L2_PIX_FMT_AV1_FRAME      PixelFormat = 'A' | 'V'<<8 | '1'<<16 | 'F'<<24 // AV1 parsed frame
+
+	// Vendor-specific formats
+	V4L2_PIX_FMT_CPIA1        PixelFormat = 'C' | 'P'<<8 | 'I'<<16 | 'A'<<24 // cpia1 YUV
+	V4L2_PIX_FMT_WNVA         PixelFormat = 'W' | 'N'<<8 | 'V'<<16 | 'A'<<24 // Winnov hw compress
+	V4L2_PIX_FMT_SN9C10X      PixelFormat = 'S' | '9'<<8 | '1'<<16 | '0'<<24 // SN9C10x compression
+	V4L2_PIX_FMT_SN9C20X_I420 PixelFormat = 'S' | '9'<<8 | '2'<<16 | '0'<<24 // SN9C20x YUV 4:2:0
+	V4L2_PIX_FMT_PWC1         PixelFormat = 'P' | 'W'<<8 | 'C'<<16 | '1'<<24 // pwc older webcam
+	V4L2_PIX_FMT_PWC2         PixelFormat = 'P' | 'W'<<8 | 'C'<<16 | '2'<<24 // pwc newer webcam
+	V4L2_PIX_FMT_ET61X251     PixelFormat = 'E' | '6'<<8 | '2'<<16 | '5'<<24 // ET61X251 compression
+	V4L2_PIX_FMT_SPCA501      PixelFormat = 'S' | '5'<<8 | '0'<<16 | '1'<<24 // YUYV per line
+	V4L2_PIX_FMT_SPCA505      PixelFormat = 'S' | '5'<<8 | '0'<<16 | '5'<<24 // YYUV per line
+	V4L2_PIX_FMT_SPCA508      PixelFormat = 'S' | '5'<<8 | '0'<<16 | '8'<<24 // YUVY per line
+	V4L2_PIX_FMT_SPCA561      PixelFormat = 'S' | '5'<<8 | '6'<<16 | '1'<<24 // compressed GBRG bayer
+	V4L2_PIX_FMT_PAC207       PixelFormat = 'P' | '2'<<8 | '0'<<16 | '7'<<24 // compressed BGGR bayer
+	V4L2_PIX_FMT_MR97310A     PixelFormat = 'M' | '3'<<8 | '1'<<16 | '0'<<24 // compressed BGGR bayer
+	V4L2_PIX_FMT_JL2005BCD    PixelFormat = 'J' | 'L'<<8 | '2'<<16 | '0'<<24 // compressed RGGB bayer
+	V4L2_PIX_FMT_SN9C2028     PixelFormat = 'S' | 'O'<<8 | 'N'<<16 | 'X'<<24 // compressed GBRG bayer
+	V4L2_PIX_FMT_SQ905C       PixelFormat = '9' | '0'<<8 | '5'<<16 | 'C'<<24 // compressed RGGB bayer
+	V4L2_PIX_FMT_PJPG         PixelFormat = 'P' | 'J'<<8 | 'P'<<16 | 'G'<<24 // Pixart 73xx JPEG
+	V4L2_PIX_FMT_OV511        PixelFormat = 'O' | '5'<<8 | '1'<<16 | '1'<<24 // ov511 JPEG
+	V4L2_PIX_FMT_OV518        PixelFormat = 'O' | '5'<<8 | '1'<<16 | '8'<<24 // ov518 JPEG
+	V4L2_PIX_FMT_STV0680      PixelFormat = 'S' | '6'<<8 | '8'<<16 | '0'<<24 // stv0680 bayer
+	V4L2_PIX_FMT_TM6000       PixelFormat = 'T' | 'M'<<8 | '6'<<16 | '0'<<24 // tm5600/tm60x0
+	V4L2_PIX_FMT_CIT_YYVYUY   PixelFormat = 'C' | 'I'<<8 | 'T'<<16 | 'V'<<24 // one line of Y then 1 line of VYUY
+	V4L2_PIX_FMT_KONICA420    PixelFormat = 'K' | 'O'<<8 | 'N'<<16 | 'I'<<24 // YUV420 planar in blocks of 256 pixels
+	V4L2_PIX_FMT_JPGL         PixelFormat = 'J' | 'P'<<8 | 'G'<<16 | 'L'<<24 // JPEG-Lite
+	V4L2_PIX_FMT_SE401        PixelFormat = 'S' | '4'<<8 | '0'<<16 | '1'<<24 // se401 janggu compressed rgb
+	V4L2_PIX_FMT_S5C_UYVY_JPG PixelFormat = 'S' | '5'<<8 | 'C'<<16 | 'I'<<24 // S5C73M3 interleaved UYVY/JPEG
+	V4L2_PIX_FMT_Y8I          PixelFormat = 'Y' | '8'<<8 | 'I'<<16 | ' '<<24 // Greyscale 8-bit L/R interleaved
+	V4L2_PIX_FMT_Y12I         PixelFormat = 'Y' | '1'<<8 | '2'<<16 | 'I'<<24 // Greyscale 12-bit L/R interleaved
+	V4L2_PIX_FMT_Z16          PixelFormat = 'Z' | '1'<<8 | '6'<<16 | ' '<<24 // Depth data 16-bit
+	V4L2_PIX_FMT_MT21C        PixelFormat = 'M' | 'T'<<8 | '2'<<16 | '1'<<24 // Mediatek compressed block mode
+	V4L2_PIX_FMT_MM21         PixelFormat = 'M' | 'M'<<8 | '2'<<16 | '1'<<24 // Mediatek 8-bit block mode, two non-contiguous planes
+	V4L2_PIX_FMT_INZI         PixelFormat = 'I' | 'N'<<8 | 'Z'<<16 | 'I'<<24 // Intel Planar Greyscale 10-bit and Depth 16-bit
+	V4L2_PIX_FMT_CNF4         PixelFormat = 'C' | 'N'<<8 | 'F'<<16 | '4'<<24 // Intel 4-bit packed depth confidence information
+	V4L2_PIX_FMT_HI240        PixelFormat = 'H' | 'I'<<8 | '2'<<16 | '4'<<24 // BTTV 8-bit dithered RGB
+	V4L2_PIX_FMT_QC08C        PixelFormat = 'Q' | '0'<<8 | '8'<<16 | 'C'<<24 // Qualcomm 8-bit compressed
+	V4L2_PIX_FMT_QC10C        PixelFormat = 'Q' | '1'<<8 | '0'<<16 | 'C'<<24 // Qualcomm 10-bit compressed
+
+	// 10bit raw packed, 32 bytes for every 25 pixels, last LSB 6 bits unused
+	V4L2_PIX_FMT_IPU3_SBGGR10 PixelFormat = 'i' | 'p'<<8 | '3'<<16 | 'b'<<24 // IPU3 packed 10-bit BGGR bayer
+	V4L2_PIX_FMT_IPU3_SGBRG10 PixelFormat = 'i' | 'p'<<8 | '3'<<16 | 'g'<<24 // IPU3 packed 10-bit GBRG bayer
+	V4L2_PIX_FMT_IPU3_SGRBG10 PixelFormat = 'i' | 'p'<<8 | '3'<<16 | 'G'<<24 // IPU3 packed 10-bit GRBG bayer
+	V4L2_PIX_FMT_IPU3_SRGGB10 PixelFormat = 'i' | 'p'<<8 | '3'<<16 | 'r'<<24 // IPU3 packed 10-bit RGGB bayer
+
+	// SDR formats - used only for Software Defined Radio devices
+	V4L2_SDR_FMT_CU8     PixelFormat = 'C' | 'U'<<8 | '0'<<16 | '8'<<24 // IQ u8
+	V4L2_SDR_FMT_CU16LE  PixelFormat = 'C' | 'U'<<8 | '1'<<16 | '6'<<24 // IQ u16le
+	V4L2_SDR_FMT_CS8     PixelFormat = 'C' | 'S'<<8 | '0'<<16 | '8'<<24 // complex s8
+	V4L2_SDR_FMT_CS14LE  PixelFormat = 'C' | 'S'<<8 | '1'<<16 | '4'<<24 // complex s14le
+	V4L2_SDR_FMT_RU12LE  PixelFormat = 'R' | 'U'<<8 | '1'<<16 | '2'<<24 // real u12le
+	V4L2_SDR_FMT_PCU16BE PixelFormat = 'P' | 'C'<<8 | '1'<<16 | '6'<<24 // planar complex u16be
+	V4L2_SDR_FMT_PCU18BE PixelFormat = 'P' | 'C'<<8 | '1'<<16 | '8'<<24 // planar complex u18be
+	V4L2_SDR_FMT_PCU20BE PixelFormat = 'P' | 'C'<<8 | '2'<<16 | '0'<<24 // planar complex u20be
+
+	// Touch formats - used for Touch devices
+	V4L2_TCH_FMT_DELTA_TD16 PixelFormat = 'T' | 'D'<<8 | '1'<<16 | '6'<<24 // 16-bit signed deltas
+	V4L2_TCH_FMT_DELTA_TD08 PixelFormat = 'T' | 'D'<<8 | '0'<<16 | '8'<<24 // 8-bit signed deltas
+	V4L2_TCH_FMT_TU16       PixelFormat = 'T' | 'U'<<8 | '1'<<16 | '6'<<24 // 16-bit unsigned touch data
+	V4L2_TCH_FMT_TU08       PixelFormat = 'T' | 'U'<<8 | '0'<<16 | '8'<<24 // 8-bit unsigned touch data
+
+	// Meta-data formats
+	V4L2_META_FMT_VSP1_HGO PixelFormat = 'V' | 'S'<<8 | 'P'<<16 | 'H'<<24 // R-Car VSP1 1-D Histogram
+	V4L2_META_FMT_VSP1_HGT PixelFormat = 'V' | 'S'<<8 | 'P'<<16 | 'T'<<24 // R-Car VSP1 2-D Histogram
+	V4L2_META_FMT_UVC      PixelFormat = 'U' | 'V'<<8 | 'C'<<16 | 'H'<<24 // UVC Payload Header metadata
+	V4L2_META_FMT_D4XX     PixelFormat = 'D' | '4'<<8 | 'X'<<16 | 'X'<<24 // D4XX Payload Header metadata
+	V4L2_META_FMT_VIVID    PixelFormat = 'V' | 'I'<<8 | 'V'<<16 | 'D'<<24 // Vivid Metadata
+
+	// Vendor specific - used for RK_ISP1 camera sub-system
+	V4L2_META_FMT_RK_ISP1_PARAMS  PixelFormat = 'R' | 'K'<<8 | '1'<<16 | 'P'<<24 // Rockchip ISP1 3A Parameters
+	V4L2_META_FMT_RK_ISP1_STAT_3A PixelFormat = 'R' | 'K'<<8 | '1'<<16 | 'S'<<24 // Rockchip ISP1 3A Statistics
+
+	// Deprecated names kept for compatibility
+	V4L2_PIX_FMT_HM12             = V4L2_PIX_FMT_NV12_16L16
+	V4L2_PIX_FMT_SUNXI_TILED_NV12 = V4L2_PIX_FMT_NV12_32L32
+)
+
+// Returns fourcc of the format with trailing spaces removed, e.g. YUYV or Y16.
+// Big-endian variants get -BE suffix, codes which are not
+// printable fourccs are returned as hex numbers
+func (f PixelFormat) String() string {
+	b := make([]byte, 4)
+	for i := range b {
+		b[i] = byte(f >> uint(i*8))
+	}
+	if f&V4L2_PIX_FMT_FLAG_BE != 0 {
+		b[3] &= 0x7f
+	}
+	for _, c := range b {
+		if c < ' ' || c > '~' {
+			return fmt.Sprintf("0x%08x", uint32(f))
+		}
+	}
+
+	s := strings.TrimRight(string(b), " ")
+	if f&V4L2_PIX_FMT_FLAG_BE != 0 {
+		s += "-BE"
+	}
+	return s
+}
+
+// Parses format returned by PixelFormat.String, e.g. MJPG, YUYV or Y16-BE.
+// Fourccs shorter than 4 characters are padded with spaces.
+// Hex codes like 0x56595559 are accepted too
+func ParsePixelFormat(s string) (PixelFormat, error) {
+	if strings.HasPrefix(s, "0x") {
+		v, err := strconv.ParseUint(s[2:], 16, 32)
+		if err != nil {
+			return 0, fmt.Errorf("Invalid pixel format '%s': %s", s, err)
+		}
+		return PixelFormat(v), nil
+	}
+
+	var f PixelFormat
+	code := s
+	if strings.HasSuffix(code, "-BE") {
+		code = strings.TrimSuffix(code, "-BE")
+		f = V4L2_PIX_FMT_FLAG_BE
+	}
+	if len(code) == 0 || len(code) > 4 {
+		return 0, fmt.Errorf("Invalid pixel format '%s': fourcc must be 1 to 4 characters long", s)
+	}
+
+	code += strings.Repeat(" ", 4-len(code))
+	for i := 0; i < 4; i++ {
+		c := code[i]
+		if c < ' ' || c > '~' {
+			return 0, fmt.Errorf("Invalid pixel format '%s': non-printable character", s)
+		}
+		f |= PixelFormat(c) << uint(i*8)
+	}
+	return f, nil
+}
+
+// Chroma subsampling of YUV formats
+type ChromaSubsampling int
+
+const (
+	// Not a YUV format
+	SubsamplingNone ChromaSubsampling = iota
+	Subsampling444
+	Subsampling422
+	Subsampling420
+	Subsampling411
+	Subsampling410
+)
+
+// Returns subsampling in J:a:b notation, e.g. 4:2:0
+func (s ChromaSubsampling) String() string {
+	switch s {
+	case Subsampling444:
+		return "4:4:4"
+	case Subsampling422:
+		return "4:2:2"
+	case Subsampling420:
+		return "4:2:0"
+	case Subsampling411:
+		return "4:1:1"
+	case Subsampling410:
+		return "4:1:0"
+	}
+	return "none"
+}
+
+// Order of color filters in the top-left 2x2 block of Bayer formats
+type BayerPattern int
+
+const (
+	// Not a Bayer format
+	BayerNone BayerPattern = iota
+	BayerBGGR
+	BayerGBRG
+	BayerGRBG
+	BayerRGGB
+)
+
+// Returns pattern name, e.g. RGGB
+func (p BayerPattern) String() string {
+	switch p {
+	case BayerBGGR:
+		return "BGGR"
+	case BayerGBRG:
+		return "GBRG"
+	case BayerGRBG:
+		return "GRBG"
+	case BayerRGGB:
+		return "RGGB"
+	}
+	return "none"
+}
+
+// Layout of image data of a pixel format
+type PixelFormatInfo struct {
+	// Frames are compressed and have variable size,
+	// other fields but Bayer are not set for compressed formats
+	Compressed bool
+
+	// Number of planes image data is split to, e.g. 2 for NV12 and 3 for YU12
+	Planes int
+
+	// Planes are stored in separate buffers,
+	// available with multi-planar API only
+	MultiPlanar bool
+
+	// Average number of bits a pixel takes in memory,
+	// including padding and subsampled chroma, e.g. 16 for Y10 and 12 for NV12
+	BitsPerPixel int
+
+	// Number of significant bits of a sample, e.g. 10 for Y10.
+	// Zero if samples have different sizes like in RGB565
+	Depth int
+
+	Subsampling ChromaSubsampling
+	Bayer       BayerPattern
+}
+
+// Returns layout of the format, false if format is not known
+// or is not an image format, e.g. metadata and SDR formats
+func (f PixelFormat) Info() (PixelFormatInfo, bool) {
+	info, ok := pixelFormats[f]
+	return info, ok
+}
+
+var pixelFormats = map[PixelFormat]PixelFormatInfo{
+	V4L2_PIX_FMT_RGB332:           {Planes: 1, BitsPerPixel: 8},
+	V4L2_PIX_FMT_RGB444:           {Planes: 1, BitsPerPixel: 16, Depth: 4},
+	V4L2_PIX_FMT_ARGB444:          {Planes: 1, BitsPerPixel: 16, Depth: 4},
+	V4L2_PIX_FMT_XRGB444:          {Planes: 1, BitsPerPixel: 16, Depth: 4},
+	V4L2_PIX_FMT_RGBA444:          {Planes: 1, BitsPerPixel: 16, Depth: 4},
+	V4L2_PIX_FMT_RGBX444:          {Planes: 1, BitsPerPixel: 16, Depth: 4},
+	V4L2_PIX_FMT_ABGR444:          {Planes: 1, BitsPerPixel: 16, Depth: 4},
+	V4L2_PIX_FMT_XBGR444:          {Planes: 1, BitsPerPixel: 16, Depth: 4},
+	V4L2_PIX_FMT_BGRA444:          {Planes: 1, BitsPerPixel: 16, Depth: 4},
+	V4L2_PIX_FMT_BGRX444:          {Planes: 1, BitsPerPixel: 16, Depth: 4},
+	V4L2_PIX_FMT_RGB555:           {Planes: 1, BitsPerPixel: 16, Depth: 5},
+	V4L2_PIX_FMT_ARGB555:          {Planes: 1, BitsPerPixel: 16, Depth: 5},
+	V4L2_PIX_FMT_XRGB555:          {Planes: 1, BitsPerPixel: 16, Depth: 5},
+	V4L2_PIX_FMT_RGBA555:          {Planes: 1, BitsPerPixel: 16, Depth: 5},
+	V4L2_PIX_FMT_RGBX555:          {Planes: 1, BitsPerPixel: 16, Depth: 5},
+	V4L2_PIX_FMT_ABGR555:          {Planes: 1, BitsPerPixel: 16, Depth: 5},
+	V4L2_PIX_FMT_XBGR555:          {Planes: 1, BitsPerPixel: 16, Depth: 5},
+	V4L2_PIX_FMT_BGRA555:          {Planes: 1, BitsPerPixel: 16, Depth: 5},
+	V4L2_PIX_FMT_BGRX555:          {Planes: 1, BitsPerPixel: 16, Depth: 5},
+	V4L2_PIX_FMT_RGB565:           {Planes: 1, BitsPerPixel: 16},
+	V4L2_PIX_FMT_RGB555X:          {Planes: 1, BitsPerPixel: 16, Depth: 5},
+	V4L2_PIX_FMT_ARGB555X:         {Planes: 1, BitsPerPixel: 16, Depth: 5},
+	V4L2_PIX_FMT_XRGB555X:         {Planes: 1, BitsPerPixel: 16, Depth: 5},
+	V4L2_PIX_FMT_RGB565X:          {Planes: 1, BitsPerPixel: 16},
+	V4L2_PIX_FMT_BGR666:           {Planes: 1, BitsPerPixel: 32, Depth: 6},
+	V4L2_PIX_FMT_BGR24:            {Planes: 1, BitsPerPixel: 24, Depth: 8},
+	V4L2_PIX_FMT_RGB24:            {Planes: 1, BitsPerPixel: 24, Depth: 8},
+	V4L2_PIX_FMT_BGR32:            {Planes: 1, BitsPerPixel: 32, Depth: 8},
+	V4L2_PIX_FMT_ABGR32:           {Planes: 1, BitsPerPixel: 32, Depth: 8},
+	V4L2_PIX_FMT_XBGR32:           {Planes: 1, BitsPerPixel: 32, Depth: 8},
+	V4L2_PIX_FMT_BGRA32:           {Planes: 1, BitsPerPixel: 32, Depth: 8},
+	V4L2_PIX_FMT_BGRX32:           {Planes: 1, BitsPerPixel: 32, Depth: 8},
+	V4L2_PIX_FMT_RGB32:            {Planes: 1, BitsPerPixel: 32, Depth: 8},
+	V4L2_PIX_FMT_RGBA32:           {Planes: 1, BitsPerPixel: 32, Depth: 8},
+	V4L2_PIX_FMT_RGBX32:           {Planes: 1, BitsPerPixel: 32, Depth: 8},
+	V4L2_PIX_FMT_ARGB32:           {Planes: 1, BitsPerPixel: 32, Depth: 8},
+	V4L2_PIX_FMT_XRGB32:           {Planes: 1, BitsPerPixel: 32, Depth: 8},
+	V4L2_PIX_FMT_GREY:             {Planes: 1, BitsPerPixel: 8, Depth: 8},
+	V4L2_PIX_FMT_Y4:               {Planes: 1, BitsPerPixel: 8, Depth: 4},
+	V4L2_PIX_FMT_Y6:               {Planes: 1, BitsPerPixel: 8, Depth: 6},
+	V4L2_PIX_FMT_Y10:              {Planes: 1, BitsPerPixel: 16, Depth: 10},
+	V4L2_PIX_FMT_Y12:              {Planes: 1, BitsPerPixel: 16, Depth: 12},
+	V4L2_PIX_FMT_Y14:              {Planes: 1, BitsPerPixel: 16, Depth: 14},
+	V4L2_PIX_FMT_Y16:              {Planes: 1, BitsPerPixel: 16, Depth: 16},
+	V4L2_PIX_FMT_Y16_BE:           {Planes: 1, BitsPerPixel: 16, Depth: 16},
+	V4L2_PIX_FMT_Y10BPACK:         {Planes: 1, BitsPerPixel: 10, Depth: 10},
+	V4L2_PIX_FMT_Y10P:             {Planes: 1, BitsPerPixel: 10, Depth: 10},
+	V4L2_PIX_FMT_Y12P:             {Planes: 1, BitsPerPixel: 12, Depth: 12},
+	V4L2_PIX_FMT_Y14P:             {Planes: 1, BitsPerPixel: 14, Depth: 14},
+	V4L2_PIX_FMT_IPU3_Y10:         {Planes: 1, BitsPerPixel: 10, Depth: 10},
+	V4L2_PIX_FMT_PAL8:             {Planes: 1, BitsPerPixel: 8},
+	V4L2_PIX_FMT_UV8:              {Planes: 1, BitsPerPixel: 8, Depth: 4},
+	V4L2_PIX_FMT_YUYV:             {Planes: 1, BitsPerPixel: 16, Depth: 8, Subsampling: Subsampling422},
+	V4L2_PIX_FMT_YYUV:             {Planes: 1, BitsPerPixel: 16, Depth: 8, Subsampling: Subsampling422},
+	V4L2_PIX_FMT_YVYU:             {Planes: 1, BitsPerPixel: 16, Depth: 8, Subsampling: Subsampling422},
+	V4L2_PIX_FMT_UYVY:             {Planes: 1, BitsPerPixel: 16, Depth: 8, Subsampling: Subsampling422},
+	V4L2_PIX_FMT_VYUY:             {Planes: 1, BitsPerPixel: 16, Depth: 8, Subsampling: Subsampling422},
+	V4L2_PIX_FMT_Y41P:             {Planes: 1, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling411},
+	V4L2_PIX_FMT_YUV444:           {Planes: 1, BitsPerPixel: 16, Depth: 4, Subsampling: Subsampling444},
+	V4L2_PIX_FMT_YUV555:           {Planes: 1, BitsPerPixel: 16, Depth: 5, Subsampling: Subsampling444},
+	V4L2_PIX_FMT_YUV565:           {Planes: 1, BitsPerPixel: 16, Subsampling: Subsampling444},
+	V4L2_PIX_FMT_YUV24:            {Planes: 1, BitsPerPixel: 24, Depth: 8, Subsampling: Subsampling444},
+	V4L2_PIX_FMT_YUV32:            {Planes: 1, BitsPerPixel: 32, Depth: 8, Subsampling: Subsampling444},
+	V4L2_PIX_FMT_AYUV32:           {Planes: 1, BitsPerPixel: 32, Depth: 8, Subsampling: Subsampling444},
+	V4L2_PIX_FMT_XYUV32:           {Planes: 1, BitsPerPixel: 32, Depth: 8, Subsampling: Subsampling444},
+	V4L2_PIX_FMT_VUYA32:           {Planes: 1, BitsPerPixel: 32, Depth: 8, Subsampling: Subsampling444},
+	V4L2_PIX_FMT_VUYX32:           {Planes: 1, BitsPerPixel: 32, Depth: 8, Subsampling: Subsampling444},
+	V4L2_PIX_FMT_YUVA32:           {Planes: 1, BitsPerPixel: 32, Depth: 8, Subsampling: Subsampling444},
+	V4L2_PIX_FMT_YUVX32:           {Planes: 1, BitsPerPixel: 32, Depth: 8, Subsampling: Subsampling444},
+	V4L2_PIX_FMT_M420:             {Planes: 1, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_NV12:             {Planes: 2, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_NV21:             {Planes: 2, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_NV16:             {Planes: 2, BitsPerPixel: 16, Depth: 8, Subsampling: Subsampling422},
+	V4L2_PIX_FMT_NV61:             {Planes: 2, BitsPerPixel: 16, Depth: 8, Subsampling: Subsampling422},
+	V4L2_PIX_FMT_NV24:             {Planes: 2, BitsPerPixel: 24, Depth: 8, Subsampling: Subsampling444},
+	V4L2_PIX_FMT_NV42:             {Planes: 2, BitsPerPixel: 24, Depth: 8, Subsampling: Subsampling444},
+	V4L2_PIX_FMT_P010:             {Planes: 2, BitsPerPixel: 24, Depth: 10, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_NV12M:            {Planes: 2, MultiPlanar: true, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_NV21M:            {Planes: 2, MultiPlanar: true, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_NV16M:            {Planes: 2, MultiPlanar: true, BitsPerPixel: 16, Depth: 8, Subsampling: Subsampling422},
+	V4L2_PIX_FMT_NV61M:            {Planes: 2, MultiPlanar: true, BitsPerPixel: 16, Depth: 8, Subsampling: Subsampling422},
+	V4L2_PIX_FMT_YUV410:           {Planes: 3, BitsPerPixel: 9, Depth: 8, Subsampling: Subsampling410},
+	V4L2_PIX_FMT_YVU410:           {Planes: 3, BitsPerPixel: 9, Depth: 8, Subsampling: Subsampling410},
+	V4L2_PIX_FMT_YUV411P:          {Planes: 3, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling411},
+	V4L2_PIX_FMT_YUV420:           {Planes: 3, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_YVU420:           {Planes: 3, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_YUV422P:          {Planes: 3, BitsPerPixel: 16, Depth: 8, Subsampling: Subsampling422},
+	V4L2_PIX_FMT_YUV420M:          {Planes: 3, MultiPlanar: true, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_YVU420M:          {Planes: 3, MultiPlanar: true, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_YUV422M:          {Planes: 3, MultiPlanar: true, BitsPerPixel: 16, Depth: 8, Subsampling: Subsampling422},
+	V4L2_PIX_FMT_YVU422M:          {Planes: 3, MultiPlanar: true, BitsPerPixel: 16, Depth: 8, Subsampling: Subsampling422},
+	V4L2_PIX_FMT_YUV444M:          {Planes: 3, MultiPlanar: true, BitsPerPixel: 24, Depth: 8, Subsampling: Subsampling444},
+	V4L2_PIX_FMT_YVU444M:          {Planes: 3, MultiPlanar: true, BitsPerPixel: 24, Depth: 8, Subsampling: Subsampling444},
+	V4L2_PIX_FMT_NV12_4L4:         {Planes: 2, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_NV12_16L16:       {Planes: 2, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_NV12_32L32:       {Planes: 2, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_P010_4L4:         {Planes: 2, BitsPerPixel: 24, Depth: 10, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_NV12MT:           {Planes: 2, MultiPlanar: true, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_NV12MT_16X16:     {Planes: 2, MultiPlanar: true, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_NV12M_8L128:      {Planes: 2, MultiPlanar: true, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_NV12M_10BE_8L128: {Planes: 2, MultiPlanar: true, BitsPerPixel: 15, Depth: 10, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_SBGGR8:           {Planes: 1, BitsPerPixel: 8, Depth: 8, Bayer: BayerBGGR},
+	V4L2_PIX_FMT_SGBRG8:           {Planes: 1, BitsPerPixel: 8, Depth: 8, Bayer: BayerGBRG},
+	V4L2_PIX_FMT_SGRBG8:           {Planes: 1, BitsPerPixel: 8, Depth: 8, Bayer: BayerGRBG},
+	V4L2_PIX_FMT_SRGGB8:           {Planes: 1, BitsPerPixel: 8, Depth: 8, Bayer: BayerRGGB},
+	V4L2_PIX_FMT_SBGGR10:          {Planes: 1, BitsPerPixel: 16, Depth: 10, Bayer: BayerBGGR},
+	V4L2_PIX_FMT_SGBRG10:          {Planes: 1, BitsPerPixel: 16, Depth: 10, Bayer: BayerGBRG},
+	V4L2_PIX_FMT_SGRBG10:          {Planes: 1, BitsPerPixel: 16, Depth: 10, Bayer: BayerGRBG},
+	V4L2_PIX_FMT_SRGGB10:          {Planes: 1, BitsPerPixel: 16, Depth: 10, Bayer: BayerRGGB},
+	V4L2_PIX_FMT_SBGGR10P:         {Planes: 1, BitsPerPixel: 10, Depth: 10, Bayer: BayerBGGR},
+	V4L2_PIX_FMT_SGBRG10P:         {Planes: 1, BitsPerPixel: 10, Depth: 10, Bayer: BayerGBRG},
+	V4L2_PIX_FMT_SGRBG10P:         {Planes: 1, BitsPerPixel: 10, Depth: 10, Bayer: BayerGRBG},
+	V4L2_PIX_FMT_SRGGB10P:         {Planes: 1, BitsPerPixel: 10, Depth: 10, Bayer: BayerRGGB},
+	V4L2_PIX_FMT_SBGGR10ALAW8:     {Planes: 1, BitsPerPixel: 8, Depth: 10, Bayer: BayerBGGR},
+	V4L2_PIX_FMT_SGBRG10ALAW8:     {Planes: 1, BitsPerPixel: 8, Depth: 10, Bayer: BayerGBRG},
+	V4L2_PIX_FMT_SGRBG10ALAW8:     {Planes: 1, BitsPerPixel: 8, Depth: 10, Bayer: BayerGRBG},
+	V4L2_PIX_FMT_SRGGB10ALAW8:     {Planes: 1, BitsPerPixel: 8, Depth: 10, Bayer: BayerRGGB},
+	V4L2_PIX_FMT_SBGGR10DPCM8:     {Planes: 1, BitsPerPixel: 8, Depth: 10, Bayer: BayerBGGR},
+	V4L2_PIX_FMT_SGBRG10DPCM8:     {Planes: 1, BitsPerPixel: 8, Depth: 10, Bayer: BayerGBRG},
+	V4L2_PIX_FMT_SGRBG10DPCM8:     {Planes: 1, BitsPerPixel: 8, Depth: 10, Bayer: BayerGRBG},
+	V4L2_PIX_FMT_SRGGB10DPCM8:     {Planes: 1, BitsPerPixel: 8, Depth: 10, Bayer: BayerRGGB},
+	V4L2_PIX_FMT_SBGGR12:          {Planes: 1, BitsPerPixel: 16, Depth: 12, Bayer: BayerBGGR},
+	V4L2_PIX_FMT_SGBRG12:          {Planes: 1, BitsPerPixel: 16, Depth: 12, Bayer: BayerGBRG},
+	V4L2_PIX_FMT_SGRBG12:          {Planes: 1, BitsPerPixel: 16, Depth: 12, Bayer: BayerGRBG},
+	V4L2_PIX_FMT_SRGGB12:          {Planes: 1, BitsPerPixel: 16, Depth: 12, Bayer: BayerRGGB},
+	V4L2_PIX_FMT_SBGGR12P:         {Planes: 1, BitsPerPixel: 12, Depth: 12, Bayer: BayerBGGR},
+	V4L2_PIX_FMT_SGBRG12P:         {Planes: 1, BitsPerPixel: 12, Depth: 12, Bayer: BayerGBRG},
+	V4L2_PIX_FMT_SGRBG12P:         {Planes: 1, BitsPerPixel: 12, Depth: 12, Bayer: BayerGRBG},
+	V4L2_PIX_FMT_SRGGB12P:         {Planes: 1, BitsPerPixel: 12, Depth: 12, Bayer: BayerRGGB},
+	V4L2_PIX_FMT_SBGGR14:          {Planes: 1, BitsPerPixel: 16, Depth: 14, Bayer: BayerBGGR},
+	V4L2_PIX_FMT_SGBRG14:          {Planes: 1, BitsPerPixel: 16, Depth: 14, Bayer: BayerGBRG},
+	V4L2_PIX_FMT_SGRBG14:          {Planes: 1, BitsPerPixel: 16, Depth: 14, Bayer: BayerGRBG},
+	V4L2_PIX_FMT_SRGGB14:          {Planes: 1, BitsPerPixel: 16, Depth: 14, Bayer: BayerRGGB},
+	V4L2_PIX_FMT_SBGGR14P:         {Planes: 1, BitsPerPixel: 14, Depth: 14, Bayer: BayerBGGR},
+	V4L2_PIX_FMT_SGBRG14P:         {Planes: 1, BitsPerPixel: 14, Depth: 14, Bayer: BayerGBRG},
+	V4L2_PIX_FMT_SGRBG14P:         {Planes: 1, BitsPerPixel: 14, Depth: 14, Bayer: BayerGRBG},
+	V4L2_PIX_FMT_SRGGB14P:         {Planes: 1, BitsPerPixel: 14, Depth: 14, Bayer: BayerRGGB},
+	V4L2_PIX_FMT_SBGGR16:          {Planes: 1, BitsPerPixel: 16, Depth: 16, Bayer: BayerBGGR},
+	V4L2_PIX_FMT_SGBRG16:          {Planes: 1, BitsPerPixel: 16, Depth: 16, Bayer: BayerGBRG},
+	V4L2_PIX_FMT_SGRBG16:          {Planes: 1, BitsPerPixel: 16, Depth: 16, Bayer: BayerGRBG},
+	V4L2_PIX_FMT_SRGGB16:          {Planes: 1, BitsPerPixel: 16, Depth: 16, Bayer: BayerRGGB},
+	V4L2_PIX_FMT_HSV24:            {Planes: 1, BitsPerPixel: 24, Depth: 8},
+	V4L2_PIX_FMT_HSV32:            {Planes: 1, BitsPerPixel: 32, Depth: 8},
+	V4L2_PIX_FMT_MJPEG:            {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_JPEG:             {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_DV:               {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_MPEG:             {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_H264:             {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_H264_NO_SC:       {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_H264_MVC:         {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_H263:             {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_MPEG1:            {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_MPEG2:            {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_MPEG2_SLICE:      {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_MPEG4:            {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_XVID:             {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_VC1_ANNEX_G:      {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_VC1_ANNEX_L:      {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_VP8:              {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_VP8_FRAME:        {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_VP9:              {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_VP9_FRAME:        {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_HEVC:             {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_FWHT:             {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_FWHT_STATELESS:   {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_H264_SLICE:       {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_HEVC_SLICE:       {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_AV1_FRAME:        {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_CPIA1:            {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_WNVA:             {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_SN9C10X:          {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_SN9C20X_I420:     {Planes: 1, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_PWC1:             {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_PWC2:             {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_ET61X251:         {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_SPCA501:          {Planes: 1, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_SPCA505:          {Planes: 1, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_SPCA508:          {Planes: 1, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_SPCA561:          {Compressed: true, Planes: 1, Bayer: BayerGBRG},
+	V4L2_PIX_FMT_PAC207:           {Compressed: true, Planes: 1, Bayer: BayerBGGR},
+	V4L2_PIX_FMT_MR97310A:         {Compressed: true, Planes: 1, Bayer: BayerBGGR},
+	V4L2_PIX_FMT_JL2005BCD:        {Compressed: true, Planes: 1, Bayer: BayerRGGB},
+	V4L2_PIX_FMT_SN9C2028:         {Compressed: true, Planes: 1, Bayer: BayerGBRG},
+	V4L2_PIX_FMT_SQ905C:           {Compressed: true, Planes: 1, Bayer: BayerRGGB},
+	V4L2_PIX_FMT_PJPG:             {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_OV511:            {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_OV518:            {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_CIT_YYVYUY:       {Planes: 1, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_KONICA420:        {Planes: 1, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_JPGL:             {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_SE401:            {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_S5C_UYVY_JPG:     {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_Y8I:              {Planes: 1, BitsPerPixel: 16, Depth: 8},
+	V4L2_PIX_FMT_Y12I:             {Planes: 1, BitsPerPixel: 24, Depth: 12},
+	V4L2_PIX_FMT_Z16:              {Planes: 1, BitsPerPixel: 16, Depth: 16},
+	V4L2_PIX_FMT_MT21C:            {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_MM21:             {Planes: 2, MultiPlanar: true, BitsPerPixel: 12, Depth: 8, Subsampling: Subsampling420},
+	V4L2_PIX_FMT_INZI:             {Planes: 2, BitsPerPixel: 32},
+	V4L2_PIX_FMT_CNF4:             {Planes: 1, BitsPerPixel: 4, Depth: 4},
+	V4L2_PIX_FMT_HI240:            {Planes: 1, BitsPerPixel: 8},
+	V4L2_PIX_FMT_QC08C:            {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_QC10C:            {Compressed: true, Planes: 1},
+	V4L2_PIX_FMT_IPU3_SBGGR10:     {Planes: 1, BitsPerPixel: 10, Depth: 10, Bayer: BayerBGGR},
+	V4L2_PIX_FMT_IPU3_SGBRG10:     {Planes: 1, BitsPerPixel: 10, Depth: 10, Bayer: BayerGBRG},
+	V4L2_PIX_FMT_IPU3_SGRBG10:     {Planes: 1, BitsPerPixel: 10, Depth: 10, Bayer: BayerGRBG},
+	V4L2_PIX_FMT_IPU3_SRGGB10:     {Planes: 1, BitsPerPixel: 10, Depth: 10, Bayer: BayerRGGB},
+	V4L2_TCH_FMT_DELTA_TD16:       {Planes: 1, BitsPerPixel: 16, Depth: 16},
+	V4L2_TCH_FMT_DELTA_TD08:       {Planes: 1, BitsPerPixel: 8, Depth: 8},
+	V4L2_TCH_FMT_TU16:             {Planes: 1, BitsPerPixel: 16, Depth: 16},
+	V4L2_TCH_FMT_TU08:             {Planes: 1, BitsPerPixel: 8, Depth: 8},
+}
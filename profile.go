@@ -0,0 +1,279 @@
+package webcam
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Version of profiles written by SaveProfile.
+// Profiles of newer versions are refused by LoadProfile
+const ProfileVersion = 1
+
+// Snapshot of camera settings: image format, frame size, frame interval,
+// input and values of all writable controls.
+// Can be stored as JSON with Save and loaded with LoadProfile
+type Profile struct {
+	Version  int
+	Format   PixelFormat
+	Width    uint32
+	Height   uint32
+	Interval Fraction
+	// Not set for devices without inputs
+	Input    *uint32 `json:",omitempty"`
+	Controls []ProfileControl
+}
+
+// Value of a control stored in a profile.
+// Name is informational only, controls are applied by ID
+type ProfileControl struct {
+	ID    ControlID
+	Name  string
+	Value int64
+}
+
+// Controls switching automatic adjustment on and off, together
+// with manual controls which can't be set while automatic mode is on
+type autoControl struct {
+	dependents []uint32
+	active     func(value int64) bool
+}
+
+func autoEnabled(value int64) bool {
+	return value != 0
+}
+
+var autoControls = map[uint32]autoControl{
+	V4L2_CID_EXPOSURE_AUTO: {
+		[]uint32{V4L2_CID_EXPOSURE_ABSOLUTE, V4L2_CID_EXPOSURE, V4L2_CID_IRIS_ABSOLUTE},
+		func(value int64) bool {
+			// Exposure time is manual in shutter priority mode too
+			return value != int64(ExposureManual) && value != int64(ExposureShutterPriority)
+		},
+	},
+	V4L2_CID_AUTO_WHITE_BALANCE: {
+		[]uint32{V4L2_CID_WHITE_BALANCE_TEMPERATURE, V4L2_CID_RED_BALANCE, V4L2_CID_BLUE_BALANCE},
+		autoEnabled,
+	},
+	V4L2_CID_AUTO_N_PRESET_WHITE_BALANCE: {
+		[]uint32{V4L2_CID_WHITE_BALANCE_TEMPERATURE},
+		autoEnabled,
+	},
+	V4L2_CID_FOCUS_AUTO:           {[]uint32{V4L2_CID_FOCUS_ABSOLUTE}, autoEnabled},
+	V4L2_CID_AUTOGAIN:             {[]uint32{V4L2_CID_GAIN}, autoEnabled},
+	V4L2_CID_HUE_AUTO:             {[]uint32{V4L2_CID_HUE}, autoEnabled},
+	V4L2_CID_AUTOBRIGHTNESS:       {[]uint32{V4L2_CID_BRIGHTNESS}, autoEnabled},
+	V4L2_CID_ISO_SENSITIVITY_AUTO: {[]uint32{V4L2_CID_ISO_SENSITIVITY}, autoEnabled},
+}
+
+type controlWrite struct {
+	id     uint32
+	c_type uint32
+	value  int64
+}
+
+// Takes a snapshot of current settings
+func (w *Webcam) SaveProfile() (*Profile, error) {
+	p := &Profile{Version: ProfileVersion}
+
+	var err error
+	p.Format, p.Width, p.Height, err = w.GetImageFormat()
+	if err != nil {
+		return nil, err
+	}
+	p.Interval, _ = w.GetFrameInterval()
+	if input, err := w.GetInput(); err == nil {
+		p.Input = &input
+	}
+
+	p.Controls = make([]ProfileControl, 0)
+	for _, c := range queryControls(w.fd) {
+		if !c.storable() {
+			continue
+		}
+		var value int64
+		if c.c_type == V4L2_CTRL_TYPE_INTEGER64 {
+			values, err := w.GetExtControls(ControlID(c.id))
+			if err != nil {
+				return nil, err
+			}
+			value = values[0].Value.(int64)
+		} else {
+			v, err := getControl(w.fd, c.id)
+			if err != nil {
+				return nil, fmt.Errorf("Failed to read control '%s': %s", c.name, err)
+			}
+			value = int64(v)
+		}
+		p.Controls = append(p.Controls, ProfileControl{ControlID(c.id), c.name, value})
+	}
+
+	return p, nil
+}
+
+// Applies settings stored in a profile.
+// Input, image format and frame interval are applied first, then
+// controls are set, so that automatic modes are switched off before
+// manual values depending on them are set. Manual values are skipped
+// if their automatic mode is on in the profile.
+// Returns ControlErrors if some controls couldn't be set.
+// Not allowed if streaming is already on
+func (w *Webcam) ApplyProfile(p *Profile) error {
+	if w.streaming {
+		return errors.New("Cannot apply profile when streaming")
+	}
+
+	if p.Input != nil {
+		err := w.SetInput(*p.Input)
+		if err != nil {
+			return err
+		}
+	}
+
+	if p.Format != 0 {
+		f, width, height, err := w.SetImageFormat(p.Format, p.Width, p.Height)
+		if err != nil {
+			return err
+		}
+		if f != p.Format || width != p.Width || height != p.Height {
+			return fmt.Errorf("Profile image format %s %dx%d is not supported, driver proposed %s %dx%d",
+				p.Format, p.Width, p.Height, f, width, height)
+		}
+	}
+
+	if p.Interval.Numerator != 0 && p.Interval.Denominator != 0 {
+		_, err := w.SetFrameInterval(p.Interval.Numerator, p.Interval.Denominator)
+		if _, unsupported := err.(*FramerateUnsupported); err != nil && !unsupported {
+			return err
+		}
+	}
+
+	types := make(map[uint32]uint32)
+	for _, c := range queryControls(w.fd) {
+		types[c.id] = c.c_type
+	}
+
+	writes := make([]controlWrite, 0, len(p.Controls))
+	errs := make(ControlErrors)
+	for _, c := range p.Controls {
+		c_type, ok := types[uint32(c.ID)]
+		if !ok {
+			errs[c.ID] = ErrControlUnsupported
+			continue
+		}
+		writes = append(writes, controlWrite{uint32(c.ID), c_type, c.Value})
+	}
+
+	for id, err := range w.writeControls(writes) {
+		errs[id] = err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
+
+// Writes profile as JSON
+func (p *Profile) Save(out io.Writer) error {
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(p)
+}
+
+// Reads profile written by Profile.Save
+func LoadProfile(in io.Reader) (*Profile, error) {
+	p := &Profile{}
+	err := json.NewDecoder(in).Decode(p)
+	if err != nil {
+		return nil, err
+	}
+	if p.Version < 1 || p.Version > ProfileVersion {
+		return nil, fmt.Errorf("Unsupported profile version %d", p.Version)
+	}
+	return p, nil
+}
+
+// Only plain values which can be both read and written are stored
+func (c control) storable() bool {
+	if (c.flags & (V4L2_CTRL_FLAG_DISABLED | V4L2_CTRL_FLAG_READ_ONLY | V4L2_CTRL_FLAG_WRITE_ONLY)) != 0 {
+		return false
+	}
+	switch c.c_type {
+	case V4L2_CTRL_TYPE_INTEGER, V4L2_CTRL_TYPE_BOOLEAN, V4L2_CTRL_TYPE_MENU,
+		V4L2_CTRL_TYPE_INTEGER_MENU, V4L2_CTRL_TYPE_BITMASK, V4L2_CTRL_TYPE_INTEGER64:
+		return true
+	}
+	return false
+}
+
+// Sets controls in dependency-safe order: automatic modes being switched
+// off first, then other controls, then automatic modes being switched on.
+// Manual controls are skipped if their automatic mode is being switched on
+func (w *Webcam) writeControls(writes []controlWrite) ControlErrors {
+	skip := make(map[uint32]bool)
+	for _, c := range writes {
+		if ac, ok := autoControls[c.id]; ok && ac.active(c.value) {
+			for _, id := range ac.dependents {
+				skip[id] = true
+			}
+		}
+	}
+
+	var autoOff, manual, autoOn []controlWrite
+	for _, c := range writes {
+		ac, ok := autoControls[c.id]
+		switch {
+		case ok && ac.active(c.value):
+			autoOn = append(autoOn, c)
+		case ok:
+			autoOff = append(autoOff, c)
+		case !skip[c.id]:
+			manual = append(manual, c)
+		}
+	}
+
+	errs := make(ControlErrors)
+	for _, phase := range [][]controlWrite{autoOff, manual, autoOn} {
+		for _, c := range phase {
+			var err error
+			if c.c_type == V4L2_CTRL_TYPE_INTEGER64 {
+				err = w.SetExtControls([]ControlValue{{ControlID(c.id), c.value}})
+			} else {
+				err = setControl(w.fd, c.id, int32(c.value))
+			}
+			if err != nil {
+				errs[ControlID(c.id)] = err
+			}
+		}
+	}
+	return errs
+}
+
+// Restores every writable control to its driver default value,
+// like a factory reset. Automatic modes are switched off before
+// manual values are set, manual values depending on automatic modes
+// which are on by default are skipped.
+// Returns ControlErrors if some controls couldn't be reset
+func (w *Webcam) ResetControls() error {
+	writes := make([]controlWrite, 0)
+	for _, c := range queryControls(w.fd) {
+		if !c.storable() {
+			continue
+		}
+		value := int64(c.def)
+		if c.c_type == V4L2_CTRL_TYPE_INTEGER64 {
+			// Legacy query truncates 64-bit defaults
+			if ext, err := queryExtControl(w.fd, c.id); err == nil {
+				value = ext.default_value
+			}
+		}
+		writes = append(writes, controlWrite{c.id, c.c_type, value})
+	}
+
+	errs := w.writeControls(writes)
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
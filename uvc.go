@@ -0,0 +1,187 @@
+package webcam
+
+import (
+	"encoding/binary"
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"github.com/blackjack/webcam/ioctl"
+)
+
+// UVC class-specific request codes.
+// See include/uapi/linux/usb/video.h
+const (
+	UVC_SET_CUR  uint8 = 0x01
+	UVC_GET_CUR  uint8 = 0x81
+	UVC_GET_MIN  uint8 = 0x82
+	UVC_GET_MAX  uint8 = 0x83
+	UVC_GET_RES  uint8 = 0x84
+	UVC_GET_LEN  uint8 = 0x85
+	UVC_GET_INFO uint8 = 0x86
+	UVC_GET_DEF  uint8 = 0x87
+)
+
+// Bits of the GET_INFO response
+const (
+	UVC_CONTROL_CAP_GET          uint8 = 1 << 0
+	UVC_CONTROL_CAP_SET          uint8 = 1 << 1
+	UVC_CONTROL_CAP_DISABLED     uint8 = 1 << 2
+	UVC_CONTROL_CAP_AUTOUPDATE   uint8 = 1 << 3
+	UVC_CONTROL_CAP_ASYNCHRONOUS uint8 = 1 << 4
+)
+
+var (
+	UVCIOC_CTRL_QUERY = ioctl.IoRW(uintptr('u'), 0x21, unsafe.Sizeof(uvc_xu_control_query{}))
+)
+
+type uvc_xu_control_query struct {
+	unit     uint8
+	selector uint8
+	query    uint8
+	size     uint16
+	data     unsafe.Pointer
+}
+
+// Performs raw queries to UVC extension units.
+// Webcam implements it with UVCIOC_CTRL_QUERY, other implementations
+// can be used to test code working with extension units without a camera
+type UVCQuerier interface {
+	// Sends a request with given code to a control (selector)
+	// of a unit. Data is read or written depending on request
+	QueryUVC(unit, selector, query uint8, data []byte) error
+}
+
+// Sends a raw request to an extension unit of a UVC camera
+func (w *Webcam) QueryUVC(unit, selector, query uint8, data []byte) error {
+	q := &uvc_xu_control_query{}
+	q.unit = unit
+	q.selector = selector
+	q.query = query
+	q.size = uint16(len(data))
+	if len(data) > 0 {
+		q.data = unsafe.Pointer(&data[0])
+	}
+	err := ioctl.Ioctl(w.fd, UVCIOC_CTRL_QUERY, uintptr(unsafe.Pointer(q)))
+	runtime.KeepAlive(data)
+	return err
+}
+
+// Capabilities of an extension unit control as reported by GET_INFO
+type UVCControlInfo uint8
+
+// Control value can be read
+func (i UVCControlInfo) CanGet() bool {
+	return uint8(i)&UVC_CONTROL_CAP_GET != 0
+}
+
+// Control value can be written
+func (i UVCControlInfo) CanSet() bool {
+	return uint8(i)&UVC_CONTROL_CAP_SET != 0
+}
+
+// Control is disabled because of an automatic mode
+func (i UVCControlInfo) Disabled() bool {
+	return uint8(i)&UVC_CONTROL_CAP_DISABLED != 0
+}
+
+// Control value may change without a request
+func (i UVCControlInfo) AutoUpdate() bool {
+	return uint8(i)&UVC_CONTROL_CAP_AUTOUPDATE != 0
+}
+
+// Control is set asynchronously
+func (i UVCControlInfo) Asynchronous() bool {
+	return uint8(i)&UVC_CONTROL_CAP_ASYNCHRONOUS != 0
+}
+
+// Vendor specific extension unit of a UVC camera.
+// Controls of the unit are addressed by selectors, their values
+// are raw bytes in a vendor defined layout
+type UVCExtensionUnit struct {
+	ID uint8
+	q  UVCQuerier
+}
+
+// Returns extension unit with given ID of the webcam.
+// Unit IDs can be found in the USB descriptors of the camera
+func (w *Webcam) ExtensionUnit(id uint8) *UVCExtensionUnit {
+	return NewUVCExtensionUnit(w, id)
+}
+
+// Returns extension unit with given ID using given querier
+func NewUVCExtensionUnit(q UVCQuerier, id uint8) *UVCExtensionUnit {
+	return &UVCExtensionUnit{id, q}
+}
+
+// Returns size of the control value in bytes
+func (u *UVCExtensionUnit) Len(selector uint8) (uint16, error) {
+	data := make([]byte, 2)
+	err := u.q.QueryUVC(u.ID, selector, UVC_GET_LEN, data)
+	if err != nil {
+		return 0, err
+	}
+	// UVC values are always little endian
+	return binary.LittleEndian.Uint16(data), nil
+}
+
+// Returns capabilities of the control
+func (u *UVCExtensionUnit) Info(selector uint8) (UVCControlInfo, error) {
+	data := make([]byte, 1)
+	err := u.q.QueryUVC(u.ID, selector, UVC_GET_INFO, data)
+	if err != nil {
+		return 0, err
+	}
+	return UVCControlInfo(data[0]), nil
+}
+
+// Returns current value of the control
+func (u *UVCExtensionUnit) Get(selector uint8) ([]byte, error) {
+	return u.get(selector, UVC_GET_CUR)
+}
+
+// Returns minimum value of the control
+func (u *UVCExtensionUnit) GetMin(selector uint8) ([]byte, error) {
+	return u.get(selector, UVC_GET_MIN)
+}
+
+// Returns maximum value of the control
+func (u *UVCExtensionUnit) GetMax(selector uint8) ([]byte, error) {
+	return u.get(selector, UVC_GET_MAX)
+}
+
+// Returns resolution (step) of the control value
+func (u *UVCExtensionUnit) GetRes(selector uint8) ([]byte, error) {
+	return u.get(selector, UVC_GET_RES)
+}
+
+// Returns default value of the control
+func (u *UVCExtensionUnit) GetDef(selector uint8) ([]byte, error) {
+	return u.get(selector, UVC_GET_DEF)
+}
+
+// Sets value of the control.
+// Data must be exactly as long as reported by Len
+func (u *UVCExtensionUnit) Set(selector uint8, data []byte) error {
+	length, err := u.Len(selector)
+	if err != nil {
+		return err
+	}
+	if int(length) != len(data) {
+		return fmt.Errorf("Invalid value size %d for unit %d selector %d, expected %d", len(data), u.ID, selector, length)
+	}
+	return u.q.QueryUVC(u.ID, selector, UVC_SET_CUR, data)
+}
+
+func (u *UVCExtensionUnit) get(selector, query uint8) ([]byte, error) {
+	length, err := u.Len(selector)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, length)
+	err = u.q.QueryUVC(u.ID, selector, query, data)
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
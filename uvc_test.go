@@ -0,0 +1,140 @@
+package webcam
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+type uvcQuery struct {
+	unit     uint8
+	selector uint8
+	query    uint8
+	length   int
+}
+
+// Records queries and answers them with responses
+// for request codes, SET_CUR data is recorded
+type fakeQuerier struct {
+	queries   []uvcQuery
+	responses map[uint8][]byte
+	written   []byte
+	err       error
+}
+
+func (f *fakeQuerier) QueryUVC(unit, selector, query uint8, data []byte) error {
+	f.queries = append(f.queries, uvcQuery{unit, selector, query, len(data)})
+	if f.err != nil {
+		return f.err
+	}
+	if query == UVC_SET_CUR {
+		f.written = append([]byte(nil), data...)
+		return nil
+	}
+	copy(data, f.responses[query])
+	return nil
+}
+
+func (f *fakeQuerier) expect(t *testing.T, queries ...uvcQuery) {
+	t.Helper()
+	if len(f.queries) != len(queries) {
+		t.Fatalf("got queries %v, expected %v", f.queries, queries)
+	}
+	for i := range queries {
+		if f.queries[i] != queries[i] {
+			t.Errorf("query %d is %+v, expected %+v", i, f.queries[i], queries[i])
+		}
+	}
+}
+
+func newFakeQuerier() *fakeQuerier {
+	return &fakeQuerier{responses: map[uint8][]byte{
+		UVC_GET_LEN:  {3, 0},
+		UVC_GET_INFO: {UVC_CONTROL_CAP_GET | UVC_CONTROL_CAP_SET},
+		UVC_GET_CUR:  {1, 2, 3},
+		UVC_GET_MIN:  {0, 0, 0},
+	}}
+}
+
+func TestUVCLen(t *testing.T) {
+	f := newFakeQuerier()
+	f.responses[UVC_GET_LEN] = []byte{0x04, 0x01}
+	length, err := NewUVCExtensionUnit(f, 4).Len(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if length != 0x104 {
+		t.Errorf("got length %d, expected %d", length, 0x104)
+	}
+	f.expect(t, uvcQuery{4, 2, UVC_GET_LEN, 2})
+}
+
+func TestUVCInfo(t *testing.T) {
+	f := newFakeQuerier()
+	info, err := NewUVCExtensionUnit(f, 4).Info(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.CanGet() || !info.CanSet() || info.Disabled() || info.AutoUpdate() || info.Asynchronous() {
+		t.Errorf("unexpected capabilities %08b", uint8(info))
+	}
+	f.expect(t, uvcQuery{4, 2, UVC_GET_INFO, 1})
+}
+
+func TestUVCInfoError(t *testing.T) {
+	f := newFakeQuerier()
+	f.err = errors.New("stall")
+	info, err := NewUVCExtensionUnit(f, 4).Info(2)
+	if err != f.err {
+		t.Fatalf("got error %v, expected %v", err, f.err)
+	}
+	if info != 0 {
+		t.Errorf("got capabilities %08b on error, expected none", uint8(info))
+	}
+}
+
+func TestUVCGet(t *testing.T) {
+	f := newFakeQuerier()
+	data, err := NewUVCExtensionUnit(f, 4).Get(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte{1, 2, 3}) {
+		t.Errorf("got value %v, expected [1 2 3]", data)
+	}
+	f.expect(t, uvcQuery{4, 2, UVC_GET_LEN, 2}, uvcQuery{4, 2, UVC_GET_CUR, 3})
+}
+
+func TestUVCGetMin(t *testing.T) {
+	f := newFakeQuerier()
+	data, err := NewUVCExtensionUnit(f, 4).GetMin(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(data, []byte{0, 0, 0}) {
+		t.Errorf("got value %v, expected [0 0 0]", data)
+	}
+	f.expect(t, uvcQuery{4, 2, UVC_GET_LEN, 2}, uvcQuery{4, 2, UVC_GET_MIN, 3})
+}
+
+func TestUVCSet(t *testing.T) {
+	f := newFakeQuerier()
+	err := NewUVCExtensionUnit(f, 4).Set(2, []byte{7, 8, 9})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(f.written, []byte{7, 8, 9}) {
+		t.Errorf("wrote %v, expected [7 8 9]", f.written)
+	}
+	f.expect(t, uvcQuery{4, 2, UVC_GET_LEN, 2}, uvcQuery{4, 2, UVC_SET_CUR, 3})
+}
+
+func TestUVCSetInvalidSize(t *testing.T) {
+	f := newFakeQuerier()
+	err := NewUVCExtensionUnit(f, 4).Set(2, []byte{7, 8})
+	if err == nil {
+		t.Fatal("value of invalid size was accepted")
+	}
+	// Nothing is written
+	f.expect(t, uvcQuery{4, 2, UVC_GET_LEN, 2})
+}
@@ -4,36 +4,122 @@ import (
 	"bytes"
 	"encoding/binary"
 	"fmt"
+	"math"
 	"unsafe"
 
 	"github.com/blackjack/webcam/ioctl"
 	"golang.org/x/sys/unix"
 )
 
-type controlType int
-
-const (
-	c_int controlType = iota
-	c_bool
-	c_menu
-)
-
 type control struct {
 	id     uint32
 	name   string
-	c_type controlType
+	c_type uint32
 	min    int32
 	max    int32
+	step   int32
+	def    int32
+	flags  uint32
+	menu   []ControlMenuItem
 }
 
 const (
 	V4L2_CAP_VIDEO_CAPTURE      uint32 = 0x00000001
 	V4L2_CAP_STREAMING          uint32 = 0x04000000
+	V4L2_CAP_TIMEPERFRAME       uint32 = 0x00001000
 	V4L2_BUF_TYPE_VIDEO_CAPTURE uint32 = 1
 	V4L2_MEMORY_MMAP            uint32 = 1
 	V4L2_FIELD_ANY              uint32 = 0
 )
 
+// Colorimetry of image formats
+const (
+	V4L2_COLORSPACE_DEFAULT       uint32 = 0
+	V4L2_COLORSPACE_SMPTE170M     uint32 = 1
+	V4L2_COLORSPACE_SMPTE240M     uint32 = 2
+	V4L2_COLORSPACE_REC709        uint32 = 3
+	V4L2_COLORSPACE_BT878         uint32 = 4
+	V4L2_COLORSPACE_470_SYSTEM_M  uint32 = 5
+	V4L2_COLORSPACE_470_SYSTEM_BG uint32 = 6
+	V4L2_COLORSPACE_JPEG          uint32 = 7
+	V4L2_COLORSPACE_SRGB          uint32 = 8
+	V4L2_COLORSPACE_OPRGB         uint32 = 9
+	V4L2_COLORSPACE_BT2020        uint32 = 10
+	V4L2_COLORSPACE_RAW           uint32 = 11
+	V4L2_COLORSPACE_DCI_P3        uint32 = 12
+
+	V4L2_YCBCR_ENC_DEFAULT          uint32 = 0
+	V4L2_YCBCR_ENC_601              uint32 = 1
+	V4L2_YCBCR_ENC_709              uint32 = 2
+	V4L2_YCBCR_ENC_XV601            uint32 = 3
+	V4L2_YCBCR_ENC_XV709            uint32 = 4
+	V4L2_YCBCR_ENC_SYCC             uint32 = 5
+	V4L2_YCBCR_ENC_BT2020           uint32 = 6
+	V4L2_YCBCR_ENC_BT2020_CONST_LUM uint32 = 7
+	V4L2_YCBCR_ENC_SMPTE240M        uint32 = 8
+
+	V4L2_QUANTIZATION_DEFAULT    uint32 = 0
+	V4L2_QUANTIZATION_FULL_RANGE uint32 = 1
+	V4L2_QUANTIZATION_LIM_RANGE  uint32 = 2
+
+	V4L2_XFER_FUNC_DEFAULT   uint32 = 0
+	V4L2_XFER_FUNC_709       uint32 = 1
+	V4L2_XFER_FUNC_SRGB      uint32 = 2
+	V4L2_XFER_FUNC_OPRGB     uint32 = 3
+	V4L2_XFER_FUNC_SMPTE240M uint32 = 4
+	V4L2_XFER_FUNC_NONE      uint32 = 5
+	V4L2_XFER_FUNC_DCI_P3    uint32 = 6
+	V4L2_XFER_FUNC_SMPTE2084 uint32 = 7
+
+	// Value of priv field of v4l2_pix_format when the fields
+	// following it are filled by the driver
+	V4L2_PIX_FMT_PRIV_MAGIC uint32 = 0xfeedcafe
+)
+
+// Flags of dequeued buffers
+const (
+	V4L2_BUF_FLAG_MAPPED   uint32 = 0x00000001
+	V4L2_BUF_FLAG_QUEUED   uint32 = 0x00000002
+	V4L2_BUF_FLAG_DONE     uint32 = 0x00000004
+	V4L2_BUF_FLAG_KEYFRAME uint32 = 0x00000008
+	V4L2_BUF_FLAG_PFRAME   uint32 = 0x00000010
+	V4L2_BUF_FLAG_BFRAME   uint32 = 0x00000020
+	V4L2_BUF_FLAG_ERROR    uint32 = 0x00000040
+	V4L2_BUF_FLAG_LAST     uint32 = 0x00100000
+)
+
+const (
+	V4L2_FMT_FLAG_COMPRESSED             uint32 = 0x0001
+	V4L2_FMT_FLAG_EMULATED               uint32 = 0x0002
+	V4L2_FMT_FLAG_CONTINUOUS_BYTESTREAM  uint32 = 0x0004
+	V4L2_FMT_FLAG_DYN_RESOLUTION         uint32 = 0x0008
+	V4L2_FMT_FLAG_ENC_CAP_FRAME_INTERVAL uint32 = 0x0010
+	V4L2_FMT_FLAG_CSC_COLORSPACE         uint32 = 0x0020
+	V4L2_FMT_FLAG_CSC_XFER_FUNC          uint32 = 0x0040
+	V4L2_FMT_FLAG_CSC_YCBCR_ENC          uint32 = 0x0080
+	V4L2_FMT_FLAG_CSC_HSV_ENC            uint32 = V4L2_FMT_FLAG_CSC_YCBCR_ENC
+	V4L2_FMT_FLAG_CSC_QUANTIZATION       uint32 = 0x0100
+)
+
+const (
+	V4L2_EVENT_ALL           uint32 = 0
+	V4L2_EVENT_VSYNC         uint32 = 1
+	V4L2_EVENT_EOS           uint32 = 2
+	V4L2_EVENT_CTRL          uint32 = 3
+	V4L2_EVENT_FRAME_SYNC    uint32 = 4
+	V4L2_EVENT_SOURCE_CHANGE uint32 = 5
+	V4L2_EVENT_MOTION_DET    uint32 = 6
+
+	V4L2_EVENT_CTRL_CH_VALUE uint32 = 0x0001
+	V4L2_EVENT_CTRL_CH_FLAGS uint32 = 0x0002
+	V4L2_EVENT_CTRL_CH_RANGE uint32 = 0x0004
+
+	V4L2_EVENT_SRC_CH_RESOLUTION uint32 = 0x0001
+
+	V4L2_EVENT_SUB_FL_SEND_INITIAL   uint32 = 0x0001
+	V4L2_EVENT_SUB_FL_ALLOW_FEEDBACK uint32 = 0x0002
+)
+
 const (
 	V4L2_FRMSIZE_TYPE_DISCRETE   uint32 = 1
 	V4L2_FRMSIZE_TYPE_CONTINUOUS uint32 = 2
@@ -41,9 +127,115 @@ const (
 )
 
 const (
-	V4L2_CID_BASE               uint32 = 0x00980900
-	V4L2_CID_AUTO_WHITE_BALANCE uint32 = V4L2_CID_BASE + 12
-	V4L2_CID_PRIVATE_BASE       uint32 = 0x08000000
+	V4L2_FRMIVAL_TYPE_DISCRETE   uint32 = 1
+	V4L2_FRMIVAL_TYPE_CONTINUOUS uint32 = 2
+	V4L2_FRMIVAL_TYPE_STEPWISE   uint32 = 3
+)
+
+const (
+	V4L2_CTRL_CLASS_USER            uint32 = 0x00980000
+	V4L2_CTRL_CLASS_CODEC           uint32 = 0x00990000
+	V4L2_CTRL_CLASS_CAMERA          uint32 = 0x009a0000
+	V4L2_CTRL_CLASS_FM_TX           uint32 = 0x009b0000
+	V4L2_CTRL_CLASS_FLASH           uint32 = 0x009c0000
+	V4L2_CTRL_CLASS_JPEG            uint32 = 0x009d0000
+	V4L2_CTRL_CLASS_IMAGE_SOURCE    uint32 = 0x009e0000
+	V4L2_CTRL_CLASS_IMAGE_PROC      uint32 = 0x009f0000
+	V4L2_CTRL_CLASS_DV              uint32 = 0x00a00000
+	V4L2_CTRL_CLASS_FM_RX           uint32 = 0x00a10000
+	V4L2_CTRL_CLASS_RF_TUNER        uint32 = 0x00a20000
+	V4L2_CTRL_CLASS_DETECT          uint32 = 0x00a30000
+	V4L2_CTRL_CLASS_CODEC_STATELESS uint32 = 0x00a40000
+	V4L2_CTRL_CLASS_COLORIMETRY     uint32 = 0x00a50000
+
+	V4L2_CTRL_CLASS_MASK uint32 = 0x0fff0000
+)
+
+const (
+	V4L2_CID_BASE                      uint32 = 0x00980900
+	V4L2_CID_USER_BASE                 uint32 = V4L2_CID_BASE
+	V4L2_CID_USER_CLASS                uint32 = 0x00980001
+	V4L2_CID_BRIGHTNESS                uint32 = V4L2_CID_BASE + 0
+	V4L2_CID_CONTRAST                  uint32 = V4L2_CID_BASE + 1
+	V4L2_CID_SATURATION                uint32 = V4L2_CID_BASE + 2
+	V4L2_CID_HUE                       uint32 = V4L2_CID_BASE + 3
+	V4L2_CID_AUDIO_VOLUME              uint32 = V4L2_CID_BASE + 5
+	V4L2_CID_AUDIO_BALANCE             uint32 = V4L2_CID_BASE + 6
+	V4L2_CID_AUDIO_BASS                uint32 = V4L2_CID_BASE + 7
+	V4L2_CID_AUDIO_TREBLE              uint32 = V4L2_CID_BASE + 8
+	V4L2_CID_AUDIO_MUTE                uint32 = V4L2_CID_BASE + 9
+	V4L2_CID_AUDIO_LOUDNESS            uint32 = V4L2_CID_BASE + 10
+	V4L2_CID_BLACK_LEVEL               uint32 = V4L2_CID_BASE + 11
+	V4L2_CID_AUTO_WHITE_BALANCE        uint32 = V4L2_CID_BASE + 12
+	V4L2_CID_DO_WHITE_BALANCE          uint32 = V4L2_CID_BASE + 13
+	V4L2_CID_RED_BALANCE               uint32 = V4L2_CID_BASE + 14
+	V4L2_CID_BLUE_BALANCE              uint32 = V4L2_CID_BASE + 15
+	V4L2_CID_GAMMA                     uint32 = V4L2_CID_BASE + 16
+	V4L2_CID_EXPOSURE                  uint32 = V4L2_CID_BASE + 17
+	V4L2_CID_AUTOGAIN                  uint32 = V4L2_CID_BASE + 18
+	V4L2_CID_GAIN                      uint32 = V4L2_CID_BASE + 19
+	V4L2_CID_HFLIP                     uint32 = V4L2_CID_BASE + 20
+	V4L2_CID_VFLIP                     uint32 = V4L2_CID_BASE + 21
+	V4L2_CID_POWER_LINE_FREQUENCY      uint32 = V4L2_CID_BASE + 24
+	V4L2_CID_HUE_AUTO                  uint32 = V4L2_CID_BASE + 25
+	V4L2_CID_WHITE_BALANCE_TEMPERATURE uint32 = V4L2_CID_BASE + 26
+	V4L2_CID_SHARPNESS                 uint32 = V4L2_CID_BASE + 27
+	V4L2_CID_BACKLIGHT_COMPENSATION    uint32 = V4L2_CID_BASE + 28
+	V4L2_CID_CHROMA_AGC                uint32 = V4L2_CID_BASE + 29
+	V4L2_CID_COLOR_KILLER              uint32 = V4L2_CID_BASE + 30
+	V4L2_CID_COLORFX                   uint32 = V4L2_CID_BASE + 31
+	V4L2_CID_AUTOBRIGHTNESS            uint32 = V4L2_CID_BASE + 32
+	V4L2_CID_BAND_STOP_FILTER          uint32 = V4L2_CID_BASE + 33
+	V4L2_CID_ROTATE                    uint32 = V4L2_CID_BASE + 34
+	V4L2_CID_BG_COLOR                  uint32 = V4L2_CID_BASE + 35
+	V4L2_CID_CHROMA_GAIN               uint32 = V4L2_CID_BASE + 36
+	V4L2_CID_ILLUMINATORS_1            uint32 = V4L2_CID_BASE + 37
+	V4L2_CID_ILLUMINATORS_2            uint32 = V4L2_CID_BASE + 38
+	V4L2_CID_MIN_BUFFERS_FOR_CAPTURE   uint32 = V4L2_CID_BASE + 39
+	V4L2_CID_MIN_BUFFERS_FOR_OUTPUT    uint32 = V4L2_CID_BASE + 40
+	V4L2_CID_ALPHA_COMPONENT           uint32 = V4L2_CID_BASE + 41
+	V4L2_CID_COLORFX_CBCR              uint32 = V4L2_CID_BASE + 42
+	V4L2_CID_PRIVATE_BASE              uint32 = 0x08000000
+)
+
+const (
+	V4L2_CID_CAMERA_CLASS_BASE           uint32 = 0x009a0900
+	V4L2_CID_CAMERA_CLASS                uint32 = 0x009a0001
+	V4L2_CID_EXPOSURE_AUTO               uint32 = V4L2_CID_CAMERA_CLASS_BASE + 1
+	V4L2_CID_EXPOSURE_ABSOLUTE           uint32 = V4L2_CID_CAMERA_CLASS_BASE + 2
+	V4L2_CID_EXPOSURE_AUTO_PRIORITY      uint32 = V4L2_CID_CAMERA_CLASS_BASE + 3
+	V4L2_CID_PAN_RELATIVE                uint32 = V4L2_CID_CAMERA_CLASS_BASE + 4
+	V4L2_CID_TILT_RELATIVE               uint32 = V4L2_CID_CAMERA_CLASS_BASE + 5
+	V4L2_CID_PAN_RESET                   uint32 = V4L2_CID_CAMERA_CLASS_BASE + 6
+	V4L2_CID_TILT_RESET                  uint32 = V4L2_CID_CAMERA_CLASS_BASE + 7
+	V4L2_CID_PAN_ABSOLUTE                uint32 = V4L2_CID_CAMERA_CLASS_BASE + 8
+	V4L2_CID_TILT_ABSOLUTE               uint32 = V4L2_CID_CAMERA_CLASS_BASE + 9
+	V4L2_CID_FOCUS_ABSOLUTE              uint32 = V4L2_CID_CAMERA_CLASS_BASE + 10
+	V4L2_CID_FOCUS_RELATIVE              uint32 = V4L2_CID_CAMERA_CLASS_BASE + 11
+	V4L2_CID_FOCUS_AUTO                  uint32 = V4L2_CID_CAMERA_CLASS_BASE + 12
+	V4L2_CID_ZOOM_ABSOLUTE               uint32 = V4L2_CID_CAMERA_CLASS_BASE + 13
+	V4L2_CID_ZOOM_RELATIVE               uint32 = V4L2_CID_CAMERA_CLASS_BASE + 14
+	V4L2_CID_ZOOM_CONTINUOUS             uint32 = V4L2_CID_CAMERA_CLASS_BASE + 15
+	V4L2_CID_PRIVACY                     uint32 = V4L2_CID_CAMERA_CLASS_BASE + 16
+	V4L2_CID_IRIS_ABSOLUTE               uint32 = V4L2_CID_CAMERA_CLASS_BASE + 17
+	V4L2_CID_IRIS_RELATIVE               uint32 = V4L2_CID_CAMERA_CLASS_BASE + 18
+	V4L2_CID_AUTO_EXPOSURE_BIAS          uint32 = V4L2_CID_CAMERA_CLASS_BASE + 19
+	V4L2_CID_AUTO_N_PRESET_WHITE_BALANCE uint32 = V4L2_CID_CAMERA_CLASS_BASE + 20
+	V4L2_CID_WIDE_DYNAMIC_RANGE          uint32 = V4L2_CID_CAMERA_CLASS_BASE + 21
+	V4L2_CID_IMAGE_STABILIZATION         uint32 = V4L2_CID_CAMERA_CLASS_BASE + 22
+	V4L2_CID_ISO_SENSITIVITY             uint32 = V4L2_CID_CAMERA_CLASS_BASE + 23
+	V4L2_CID_ISO_SENSITIVITY_AUTO        uint32 = V4L2_CID_CAMERA_CLASS_BASE + 24
+	V4L2_CID_EXPOSURE_METERING           uint32 = V4L2_CID_CAMERA_CLASS_BASE + 25
+	V4L2_CID_SCENE_MODE                  uint32 = V4L2_CID_CAMERA_CLASS_BASE + 26
+	V4L2_CID_3A_LOCK                     uint32 = V4L2_CID_CAMERA_CLASS_BASE + 27
+	V4L2_CID_AUTO_FOCUS_START            uint32 = V4L2_CID_CAMERA_CLASS_BASE + 28
+	V4L2_CID_AUTO_FOCUS_STOP             uint32 = V4L2_CID_CAMERA_CLASS_BASE + 29
+	V4L2_CID_AUTO_FOCUS_STATUS           uint32 = V4L2_CID_CAMERA_CLASS_BASE + 30
+	V4L2_CID_AUTO_FOCUS_RANGE            uint32 = V4L2_CID_CAMERA_CLASS_BASE + 31
+	V4L2_CID_PAN_SPEED                   uint32 = V4L2_CID_CAMERA_CLASS_BASE + 32
+	V4L2_CID_TILT_SPEED                  uint32 = V4L2_CID_CAMERA_CLASS_BASE + 33
+	V4L2_CID_CAMERA_ORIENTATION          uint32 = V4L2_CID_CAMERA_CLASS_BASE + 34
+	V4L2_CID_CAMERA_SENSOR_ROTATION      uint32 = V4L2_CID_CAMERA_CLASS_BASE + 35
 )
 
 const (
@@ -64,13 +256,25 @@ const (
 )
 
 const (
-	V4L2_CTRL_FLAG_DISABLED  uint32 = 0x00000001
-	V4L2_CTRL_FLAG_NEXT_CTRL uint32 = 0x80000000
+	V4L2_CTRL_FLAG_DISABLED         uint32 = 0x00000001
+	V4L2_CTRL_FLAG_GRABBED          uint32 = 0x00000002
+	V4L2_CTRL_FLAG_READ_ONLY        uint32 = 0x00000004
+	V4L2_CTRL_FLAG_UPDATE           uint32 = 0x00000008
+	V4L2_CTRL_FLAG_INACTIVE         uint32 = 0x00000010
+	V4L2_CTRL_FLAG_SLIDER           uint32 = 0x00000020
+	V4L2_CTRL_FLAG_WRITE_ONLY       uint32 = 0x00000040
+	V4L2_CTRL_FLAG_VOLATILE         uint32 = 0x00000080
+	V4L2_CTRL_FLAG_HAS_PAYLOAD      uint32 = 0x00000100
+	V4L2_CTRL_FLAG_EXECUTE_ON_WRITE uint32 = 0x00000200
+	V4L2_CTRL_FLAG_MODIFY_LAYOUT    uint32 = 0x00000400
+	V4L2_CTRL_FLAG_NEXT_CTRL        uint32 = 0x80000000
+	V4L2_CTRL_FLAG_NEXT_COMPOUND    uint32 = 0x40000000
 )
 
 var (
 	VIDIOC_QUERYCAP  = ioctl.IoR(uintptr('V'), 0, unsafe.Sizeof(v4l2_capability{}))
 	VIDIOC_ENUM_FMT  = ioctl.IoRW(uintptr('V'), 2, unsafe.Sizeof(v4l2_fmtdesc{}))
+	VIDIOC_G_FMT     = ioctl.IoRW(uintptr('V'), 4, unsafe.Sizeof(v4l2_format{}))
 	VIDIOC_S_FMT     = ioctl.IoRW(uintptr('V'), 5, unsafe.Sizeof(v4l2_format{}))
 	VIDIOC_REQBUFS   = ioctl.IoRW(uintptr('V'), 8, unsafe.Sizeof(v4l2_requestbuffers{}))
 	VIDIOC_QUERYBUF  = ioctl.IoRW(uintptr('V'), 9, unsafe.Sizeof(v4l2_buffer{}))
@@ -80,13 +284,26 @@ var (
 	VIDIOC_S_PARM    = ioctl.IoRW(uintptr('V'), 22, unsafe.Sizeof(v4l2_streamparm{}))
 	VIDIOC_G_CTRL    = ioctl.IoRW(uintptr('V'), 27, unsafe.Sizeof(v4l2_control{}))
 	VIDIOC_S_CTRL    = ioctl.IoRW(uintptr('V'), 28, unsafe.Sizeof(v4l2_control{}))
+	VIDIOC_ENUMINPUT = ioctl.IoRW(uintptr('V'), 26, unsafe.Sizeof(v4l2_input{}))
 	VIDIOC_QUERYCTRL = ioctl.IoRW(uintptr('V'), 36, unsafe.Sizeof(v4l2_queryctrl{}))
+	VIDIOC_QUERYMENU = ioctl.IoRW(uintptr('V'), 37, unsafe.Sizeof(v4l2_querymenu{}))
+	VIDIOC_TRY_FMT   = ioctl.IoRW(uintptr('V'), 64, unsafe.Sizeof(v4l2_format{}))
 	//sizeof int32
-	VIDIOC_STREAMON        = ioctl.IoW(uintptr('V'), 18, 4)
-	VIDIOC_STREAMOFF       = ioctl.IoW(uintptr('V'), 19, 4)
-	VIDIOC_ENUM_FRAMESIZES = ioctl.IoRW(uintptr('V'), 74, unsafe.Sizeof(v4l2_frmsizeenum{}))
-	__p                    = unsafe.Pointer(uintptr(0))
-	NativeByteOrder        = getNativeByteOrder()
+	VIDIOC_STREAMON            = ioctl.IoW(uintptr('V'), 18, 4)
+	VIDIOC_STREAMOFF           = ioctl.IoW(uintptr('V'), 19, 4)
+	VIDIOC_G_INPUT             = ioctl.IoR(uintptr('V'), 38, 4)
+	VIDIOC_S_INPUT             = ioctl.IoRW(uintptr('V'), 39, 4)
+	VIDIOC_ENUM_FRAMESIZES     = ioctl.IoRW(uintptr('V'), 74, unsafe.Sizeof(v4l2_frmsizeenum{}))
+	VIDIOC_ENUM_FRAMEINTERVALS = ioctl.IoRW(uintptr('V'), 75, unsafe.Sizeof(v4l2_frmivalenum{}))
+	VIDIOC_G_EXT_CTRLS         = ioctl.IoRW(uintptr('V'), 71, unsafe.Sizeof(v4l2_ext_controls{}))
+	VIDIOC_S_EXT_CTRLS         = ioctl.IoRW(uintptr('V'), 72, unsafe.Sizeof(v4l2_ext_controls{}))
+	VIDIOC_TRY_EXT_CTRLS       = ioctl.IoRW(uintptr('V'), 73, unsafe.Sizeof(v4l2_ext_controls{}))
+	VIDIOC_QUERY_EXT_CTRL      = ioctl.IoRW(uintptr('V'), 103, unsafe.Sizeof(v4l2_query_ext_ctrl{}))
+	VIDIOC_DQEVENT             = ioctl.IoR(uintptr('V'), 89, unsafe.Sizeof(v4l2_event{}))
+	VIDIOC_SUBSCRIBE_EVENT     = ioctl.IoW(uintptr('V'), 90, unsafe.Sizeof(v4l2_event_subscription{}))
+	VIDIOC_UNSUBSCRIBE_EVENT   = ioctl.IoW(uintptr('V'), 91, unsafe.Sizeof(v4l2_event_subscription{}))
+	__p                        = unsafe.Pointer(uintptr(0))
+	NativeByteOrder            = getNativeByteOrder()
 )
 
 type v4l2_capability struct {
@@ -130,6 +347,30 @@ type v4l2_frmsize_stepwise struct {
 	Step_height uint32
 }
 
+type v4l2_frmivalenum struct {
+	index        uint32
+	pixel_format uint32
+	width        uint32
+	height       uint32
+	_type        uint32
+	union        [24]uint8
+	reserved     [2]uint32
+}
+
+type v4l2_frmival_discrete struct {
+	Numerator   uint32
+	Denominator uint32
+}
+
+type v4l2_frmival_stepwise struct {
+	Min_numerator    uint32
+	Min_denominator  uint32
+	Max_numerator    uint32
+	Max_denominator  uint32
+	Step_numerator   uint32
+	Step_denominator uint32
+}
+
 //Hack to make go compiler properly align union
 type v4l2_format_aligned_union struct {
 	data [200 - unsafe.Sizeof(__p)]byte
@@ -201,11 +442,98 @@ type v4l2_queryctrl struct {
 	reserved      [2]uint32
 }
 
+type v4l2_input struct {
+	index        uint32
+	name         [32]uint8
+	_type        uint32
+	audioset     uint32
+	tuner        uint32
+	std          uint64
+	status       uint32
+	capabilities uint32
+	reserved     [3]uint32
+}
+
+// Union of name and value is read depending on control type
+type v4l2_querymenu struct {
+	id       uint32
+	index    uint32
+	union    [32]uint8
+	reserved uint32
+}
+
 type v4l2_control struct {
 	id    uint32
 	value int32
 }
 
+type v4l2_query_ext_ctrl struct {
+	id            uint32
+	_type         uint32
+	name          [32]uint8
+	minimum       int64
+	maximum       int64
+	step          uint64
+	default_value int64
+	flags         uint32
+	elem_size     uint32
+	elems         uint32
+	nr_of_dims    uint32
+	dims          [4]uint32
+	reserved      [32]uint32
+}
+
+// The structure is packed, so union of value and pointers is kept as bytes
+type v4l2_ext_control struct {
+	id        uint32
+	size      uint32
+	reserved2 uint32
+	union     [8]uint8
+}
+
+type v4l2_ext_controls struct {
+	which     uint32
+	count     uint32
+	error_idx uint32
+	reserved  [2]uint32
+	controls  unsafe.Pointer
+}
+
+type v4l2_event_subscription struct {
+	_type    uint32
+	id       uint32
+	flags    uint32
+	reserved [5]uint32
+}
+
+// Union contains 64-bit values, so it is aligned the same way
+type v4l2_event_union struct {
+	_    [0]uint64
+	data [64]uint8
+}
+
+type v4l2_event struct {
+	_type     uint32
+	union     v4l2_event_union
+	pending   uint32
+	sequence  uint32
+	timestamp unix.Timespec
+	id        uint32
+	reserved  [8]uint32
+}
+
+// Value is a union of 32-bit and 64-bit values
+type v4l2_event_ctrl struct {
+	Changes       uint32
+	Type          uint32
+	Value         [8]uint8
+	Flags         uint32
+	Minimum       int32
+	Maximum       int32
+	Step          int32
+	Default_value int32
+}
+
 type v4l2_fract struct {
 	numerator   uint32
 	denominator uint32
@@ -228,9 +556,7 @@ type v4l2_streamparm struct {
 
 func checkCapabilities(fd uintptr) (supportsVideoCapture bool, supportsVideoStreaming bool, err error) {
 
-	caps := &v4l2_capability{}
-
-	err = ioctl.Ioctl(fd, VIDIOC_QUERYCAP, uintptr(unsafe.Pointer(caps)))
+	caps, err := getCapability(fd)
 
 	if err != nil {
 		return
@@ -242,7 +568,16 @@ func checkCapabilities(fd uintptr) (supportsVideoCapture bool, supportsVideoStre
 
 }
 
-func getPixelFormat(fd uintptr, index uint32) (code uint32, description string, err error) {
+func getCapability(fd uintptr) (caps *v4l2_capability, err error) {
+
+	caps = &v4l2_capability{}
+
+	err = ioctl.Ioctl(fd, VIDIOC_QUERYCAP, uintptr(unsafe.Pointer(caps)))
+	return
+
+}
+
+func getPixelFormat(fd uintptr, index uint32) (code uint32, description string, flags uint32, err error) {
 
 	fmtdesc := &v4l2_fmtdesc{}
 
@@ -257,6 +592,7 @@ func getPixelFormat(fd uintptr, index uint32) (code uint32, description string,
 
 	code = fmtdesc.pixelformat
 	description = CToGoString(fmtdesc.description[:])
+	flags = fmtdesc.flags
 
 	return
 }
@@ -290,9 +626,8 @@ func getFrameSize(fd uintptr, index uint32, code uint32) (frameSize FrameSize, e
 		frameSize.MaxHeight = discrete.Height
 		frameSize.StepHeight = 0
 
-	case V4L2_FRMSIZE_TYPE_CONTINUOUS:
-
-	case V4L2_FRMSIZE_TYPE_STEPWISE:
+	case V4L2_FRMSIZE_TYPE_CONTINUOUS, V4L2_FRMSIZE_TYPE_STEPWISE:
+		// Continuous sizes use the stepwise layout with a step of 1
 		stepwise := &v4l2_frmsize_stepwise{}
 		err = binary.Read(bytes.NewBuffer(frmsizeenum.union[:]), NativeByteOrder, stepwise)
 
@@ -311,47 +646,140 @@ func getFrameSize(fd uintptr, index uint32, code uint32) (frameSize FrameSize, e
 	return
 }
 
-func setImageFormat(fd uintptr, formatcode *uint32, width *uint32, height *uint32) (err error) {
+func getFrameInterval(fd uintptr, index uint32, code uint32, width uint32, height uint32) (frameInterval FrameInterval, err error) {
 
-	format := &v4l2_format{
-		_type: V4L2_BUF_TYPE_VIDEO_CAPTURE,
+	frmivalenum := &v4l2_frmivalenum{}
+	frmivalenum.index = index
+	frmivalenum.pixel_format = code
+	frmivalenum.width = width
+	frmivalenum.height = height
+
+	err = ioctl.Ioctl(fd, VIDIOC_ENUM_FRAMEINTERVALS, uintptr(unsafe.Pointer(frmivalenum)))
+
+	if err != nil {
+		return
+	}
+
+	switch frmivalenum._type {
+
+	case V4L2_FRMIVAL_TYPE_DISCRETE:
+		discrete := &v4l2_frmival_discrete{}
+		err = binary.Read(bytes.NewBuffer(frmivalenum.union[:]), NativeByteOrder, discrete)
+
+		if err != nil {
+			return
+		}
+
+		frameInterval.Min = Fraction{discrete.Numerator, discrete.Denominator}
+		frameInterval.Max = frameInterval.Min
+
+	case V4L2_FRMIVAL_TYPE_CONTINUOUS, V4L2_FRMIVAL_TYPE_STEPWISE:
+		// Continuous intervals use the stepwise layout with a step of 1/1
+		stepwise := &v4l2_frmival_stepwise{}
+		err = binary.Read(bytes.NewBuffer(frmivalenum.union[:]), NativeByteOrder, stepwise)
+
+		if err != nil {
+			return
+		}
+
+		frameInterval.Min = Fraction{stepwise.Min_numerator, stepwise.Min_denominator}
+		frameInterval.Max = Fraction{stepwise.Max_numerator, stepwise.Max_denominator}
+		frameInterval.Step = Fraction{stepwise.Step_numerator, stepwise.Step_denominator}
+	}
+
+	return
+}
+
+func getInput(fd uintptr, index uint32) (input Input, err error) {
+
+	inp := &v4l2_input{}
+	inp.index = index
+
+	err = ioctl.Ioctl(fd, VIDIOC_ENUMINPUT, uintptr(unsafe.Pointer(inp)))
+
+	if err != nil {
+		return
 	}
 
-	pix := v4l2_pix_format{
+	input.Index = inp.index
+	input.Name = CToGoString(inp.name[:])
+	input.Type = inp._type
+	input.Status = inp.status
+	input.Capabilities = inp.capabilities
+
+	return
+}
+
+func getImageFormat(fd uintptr, formatcode *uint32, width *uint32, height *uint32) (err error) {
+	return imageFormat(fd, VIDIOC_G_FMT, formatcode, width, height)
+}
+
+func getCurrentInput(fd uintptr) (uint32, error) {
+	var index uint32
+	err := ioctl.Ioctl(fd, VIDIOC_G_INPUT, uintptr(unsafe.Pointer(&index)))
+	return index, err
+}
+
+func setCurrentInput(fd uintptr, index uint32) error {
+	return ioctl.Ioctl(fd, VIDIOC_S_INPUT, uintptr(unsafe.Pointer(&index)))
+}
+
+func setImageFormat(fd uintptr, formatcode *uint32, width *uint32, height *uint32) (err error) {
+	return imageFormat(fd, VIDIOC_S_FMT, formatcode, width, height)
+}
+
+// Same as setImageFormat, but doesn't change the state of the device
+func tryImageFormat(fd uintptr, formatcode *uint32, width *uint32, height *uint32) (err error) {
+	return imageFormat(fd, VIDIOC_TRY_FMT, formatcode, width, height)
+}
+
+func imageFormat(fd uintptr, request uintptr, formatcode *uint32, width *uint32, height *uint32) (err error) {
+
+	pix := &v4l2_pix_format{
 		Width:       *width,
 		Height:      *height,
 		Pixelformat: *formatcode,
 		Field:       V4L2_FIELD_ANY,
 	}
 
-	pixbytes := &bytes.Buffer{}
-	err = binary.Write(pixbytes, NativeByteOrder, pix)
+	err = pixFormat(fd, request, pix)
 
 	if err != nil {
 		return
 	}
 
-	copy(format.union.data[:], pixbytes.Bytes())
+	*width = pix.Width
+	*height = pix.Height
+	*formatcode = pix.Pixelformat
 
-	err = ioctl.Ioctl(fd, VIDIOC_S_FMT, uintptr(unsafe.Pointer(format)))
+	return
 
-	if err != nil {
-		return
+}
+
+// Sends pix with given request and updates it with the format
+// returned by the driver
+func pixFormat(fd uintptr, request uintptr, pix *v4l2_pix_format) (err error) {
+
+	format := &v4l2_format{
+		_type: V4L2_BUF_TYPE_VIDEO_CAPTURE,
 	}
 
-	pixReverse := &v4l2_pix_format{}
-	err = binary.Read(bytes.NewBuffer(format.union.data[:]), NativeByteOrder, pixReverse)
+	pixbytes := &bytes.Buffer{}
+	err = binary.Write(pixbytes, NativeByteOrder, pix)
 
 	if err != nil {
 		return
 	}
 
-	*width = pixReverse.Width
-	*height = pixReverse.Height
-	*formatcode = pixReverse.Pixelformat
+	copy(format.union.data[:], pixbytes.Bytes())
 
-	return
+	err = ioctl.Ioctl(fd, request, uintptr(unsafe.Pointer(format)))
+
+	if err != nil {
+		return
+	}
 
+	return binary.Read(bytes.NewBuffer(format.union.data[:]), NativeByteOrder, pix)
 }
 
 func mmapRequestBuffers(fd uintptr, buf_count *uint32) (err error) {
@@ -400,7 +828,7 @@ func mmapQueryBuffer(fd uintptr, index uint32, length *uint32) (buffer []byte, e
 	return
 }
 
-func mmapDequeueBuffer(fd uintptr, index *uint32, length *uint32) (err error) {
+func mmapDequeueBuffer(fd uintptr, index *uint32, length *uint32, flags *uint32) (err error) {
 
 	buffer := &v4l2_buffer{}
 
@@ -415,6 +843,7 @@ func mmapDequeueBuffer(fd uintptr, index *uint32, length *uint32) (err error) {
 
 	*index = buffer.index
 	*length = buffer.bytesused
+	*flags = buffer.flags
 
 	return
 
@@ -454,25 +883,95 @@ func stopStreaming(fd uintptr) (err error) {
 
 }
 
-func waitForFrame(fd uintptr, timeout uint32) (count int, err error) {
+// Waits until a frame can be dequeued.
+// If onEvent is set, pending events are reported to it
+// while waiting, so it can dequeue them. Waiting is stopped
+// if onEvent returns true
+func waitForFrame(fd uintptr, timeout uint32, onEvent func() bool) (count int, err error) {
+
+	var oneSecInNsec int64 = 1e9
+	timeoutNsec := int64(timeout) * oneSecInNsec
+	nativeTimeVal := unix.NsecToTimeval(timeoutNsec)
+	tv := &nativeTimeVal
 
 	for {
 		fds := &unix.FdSet{}
 		fds.Set(int(fd))
 
-		var oneSecInNsec int64 = 1e9
-		timeoutNsec := int64(timeout) * oneSecInNsec
-		nativeTimeVal := unix.NsecToTimeval(timeoutNsec)
-		tv := &nativeTimeVal
+		// Events are signaled as exceptional condition
+		var efds *unix.FdSet
+		if onEvent != nil {
+			efds = &unix.FdSet{}
+			efds.Set(int(fd))
+		}
 
-		count, err = unix.Select(int(fd+1), fds, nil, nil, tv)
+		// Linux updates tv with remaining time, so waiting
+		// can be resumed after an interrupt or an event
+		count, err = unix.Select(int(fd+1), fds, nil, efds, tv)
 
 		if count < 0 && err == unix.EINTR {
 			continue
 		}
+		if err == nil && efds != nil && efds.IsSet(int(fd)) {
+			stop := onEvent()
+			if !stop && !fds.IsSet(int(fd)) {
+				continue
+			}
+		}
+		return
+	}
+
+}
+
+func subscribeEvent(fd uintptr, _type uint32, id uint32, flags uint32) error {
+	sub := &v4l2_event_subscription{}
+	sub._type = _type
+	sub.id = id
+	sub.flags = flags
+	return ioctl.Ioctl(fd, VIDIOC_SUBSCRIBE_EVENT, uintptr(unsafe.Pointer(sub)))
+}
+
+func unsubscribeEvent(fd uintptr, _type uint32, id uint32) error {
+	sub := &v4l2_event_subscription{}
+	sub._type = _type
+	sub.id = id
+	return ioctl.Ioctl(fd, VIDIOC_UNSUBSCRIBE_EVENT, uintptr(unsafe.Pointer(sub)))
+}
+
+func dequeueEvent(fd uintptr) (*v4l2_event, error) {
+	event := &v4l2_event{}
+	err := ioctl.Ioctl(fd, VIDIOC_DQEVENT, uintptr(unsafe.Pointer(event)))
+	return event, err
+}
+
+func parseControlEvent(event *v4l2_event) (e ControlEvent, err error) {
+	ctrl := &v4l2_event_ctrl{}
+	err = binary.Read(bytes.NewBuffer(event.union.data[:]), NativeByteOrder, ctrl)
+	if err != nil {
 		return
 	}
 
+	e.ID = ControlID(event.id)
+	e.Changes = ctrl.Changes
+	e.Type = ctrl.Type
+	if ctrl.Type == V4L2_CTRL_TYPE_INTEGER64 {
+		e.Value = int64(NativeByteOrder.Uint64(ctrl.Value[:]))
+	} else {
+		e.Value = int64(int32(NativeByteOrder.Uint32(ctrl.Value[:])))
+	}
+	e.Flags = ctrl.Flags
+	e.Min = ctrl.Minimum
+	e.Max = ctrl.Maximum
+	e.Step = ctrl.Step
+	e.Default = ctrl.Default_value
+	return
+}
+
+func supportsControl(fd uintptr, id uint32) bool {
+	query := &v4l2_queryctrl{}
+	query.id = id
+	err := ioctl.Ioctl(fd, VIDIOC_QUERYCTRL, uintptr(unsafe.Pointer(query)))
+	return err == nil && (query.flags&V4L2_CTRL_FLAG_DISABLED) == 0
 }
 
 func getControl(fd uintptr, id uint32) (int32, error) {
@@ -483,42 +982,159 @@ func getControl(fd uintptr, id uint32) (int32, error) {
 }
 
 func setControl(fd uintptr, id uint32, val int32) error {
+	_, err := setControlValue(fd, id, val)
+	return err
+}
+
+// Same as setControl, but returns the value
+// the driver actually applied
+func setControlValue(fd uintptr, id uint32, val int32) (int32, error) {
 	ctrl := &v4l2_control{}
 	ctrl.id = id
 	ctrl.value = val
-	return ioctl.Ioctl(fd, VIDIOC_S_CTRL, uintptr(unsafe.Pointer(ctrl)))
+	err := ioctl.Ioctl(fd, VIDIOC_S_CTRL, uintptr(unsafe.Pointer(ctrl)))
+	return ctrl.value, err
 }
 
-func getFramerate(fd uintptr) (float32, error) {
+func queryControl(fd uintptr, id uint32) (c control, err error) {
+	query := &v4l2_queryctrl{}
+	query.id = id
+	err = ioctl.Ioctl(fd, VIDIOC_QUERYCTRL, uintptr(unsafe.Pointer(query)))
+	if err != nil {
+		return
+	}
+	c.id = query.id
+	c.name = CToGoString(query.name[:])
+	c.c_type = query._type
+	c.min = query.minimum
+	c.max = query.maximum
+	c.step = query.step
+	c.def = query.default_value
+	c.flags = query.flags
+	if c.c_type == V4L2_CTRL_TYPE_MENU || c.c_type == V4L2_CTRL_TYPE_INTEGER_MENU {
+		c.menu = queryMenu(fd, c.id, c.c_type, c.min, c.max)
+	}
+	return
+}
+
+func queryExtControl(fd uintptr, id uint32) (*v4l2_query_ext_ctrl, error) {
+	query := &v4l2_query_ext_ctrl{}
+	query.id = id
+	err := ioctl.Ioctl(fd, VIDIOC_QUERY_EXT_CTRL, uintptr(unsafe.Pointer(query)))
+	return query, err
+}
+
+// Returns type and payload layout of a control.
+// Falls back to VIDIOC_QUERYCTRL for kernels without VIDIOC_QUERY_EXT_CTRL
+func controlLayout(fd uintptr, id uint32) (c_type uint32, elemSize uint32, elems uint32, err error) {
+	ext, err := queryExtControl(fd, id)
+	if err == nil {
+		return ext._type, ext.elem_size, ext.elems, nil
+	}
+
+	query := &v4l2_queryctrl{}
+	query.id = id
+	err = ioctl.Ioctl(fd, VIDIOC_QUERYCTRL, uintptr(unsafe.Pointer(query)))
+	if err != nil {
+		return
+	}
+	c_type = query._type
+	elems = 1
+	switch c_type {
+	case V4L2_CTRL_TYPE_INTEGER64:
+		elemSize = 8
+	case V4L2_CTRL_TYPE_STRING:
+		elemSize = uint32(query.maximum) + 1
+	default:
+		elemSize = 4
+	}
+	return
+}
+
+func extControls(fd uintptr, request uintptr, ctrls []v4l2_ext_control) (errorIdx uint32, err error) {
+	if len(ctrls) == 0 {
+		return
+	}
+	req := &v4l2_ext_controls{}
+	req.count = uint32(len(ctrls))
+	req.controls = unsafe.Pointer(&ctrls[0])
+	err = ioctl.Ioctl(fd, request, uintptr(unsafe.Pointer(req)))
+	return req.error_idx, err
+}
+
+// Stores pointer to a payload in the union of v4l2_ext_control.
+// Payload must be kept alive until ioctl returns
+func (c *v4l2_ext_control) setPointer(p unsafe.Pointer) {
+	if unsafe.Sizeof(uintptr(0)) == 8 {
+		NativeByteOrder.PutUint64(c.union[:], uint64(uintptr(p)))
+	} else {
+		NativeByteOrder.PutUint32(c.union[:], uint32(uintptr(p)))
+	}
+}
+
+func getStreamParm(fd uintptr) (*v4l2_streamparm, error) {
 	param := &v4l2_streamparm{}
 	param._type = V4L2_BUF_TYPE_VIDEO_CAPTURE
 
 	err := ioctl.Ioctl(fd, VIDIOC_G_PARM, uintptr(unsafe.Pointer(param)))
+	return param, err
+}
+
+func getFramerate(fd uintptr) (float32, error) {
+	tf, err := getTimePerFrame(fd)
 	if err != nil {
 		return 0, err
 	}
+	return float32(tf.Denominator) / float32(tf.Numerator), nil
+}
+
+func getTimePerFrame(fd uintptr) (Fraction, error) {
+	param, err := getStreamParm(fd)
+	if err != nil {
+		return Fraction{}, err
+	}
 	tf := param.union.time_per_frame
 	if tf.denominator == 0 || tf.numerator == 0 {
-		return 0, fmt.Errorf("Invalid framerate (%d/%d)", tf.denominator, tf.numerator)
+		return Fraction{}, fmt.Errorf("Invalid frame interval (%d/%d)", tf.numerator, tf.denominator)
 	}
-	return float32(tf.denominator) / float32(tf.numerator), nil
+	return Fraction{tf.numerator, tf.denominator}, nil
 }
 
-func setFramerate(fd uintptr, num, denom uint32) error {
+func supportsTimePerFrame(fd uintptr) (bool, error) {
+	param, err := getStreamParm(fd)
+	if err != nil {
+		return false, err
+	}
+	return (param.union.capability & V4L2_CAP_TIMEPERFRAME) != 0, nil
+}
+
+func setTimePerFrame(fd uintptr, num, denom uint32) (Fraction, error) {
 	param := &v4l2_streamparm{}
 	param._type = V4L2_BUF_TYPE_VIDEO_CAPTURE
 	param.union.time_per_frame.numerator = num
 	param.union.time_per_frame.denominator = denom
-	return ioctl.Ioctl(fd, VIDIOC_S_PARM, uintptr(unsafe.Pointer(param)))
+	err := ioctl.Ioctl(fd, VIDIOC_S_PARM, uintptr(unsafe.Pointer(param)))
+	if err != nil {
+		return Fraction{}, err
+	}
+	// Driver writes back the interval it actually applied
+	tf := param.union.time_per_frame
+	return Fraction{tf.numerator, tf.denominator}, nil
 }
 
+// Returns all controls including class entries in driver order.
+// Uses VIDIOC_QUERY_EXT_CTRL where available
 func queryControls(fd uintptr) []control {
-	controls := []control{}
-	var err error
+	controls, err := queryExtControls(fd)
+	if err == nil {
+		return controls
+	}
+
+	controls = []control{}
 	// Don't use V42L_CID_BASE since it is the same as brightness.
 	var id uint32
 	for err == nil {
-		id |= V4L2_CTRL_FLAG_NEXT_CTRL
+		id |= V4L2_CTRL_FLAG_NEXT_CTRL | V4L2_CTRL_FLAG_NEXT_COMPOUND
 		query := &v4l2_queryctrl{}
 		query.id = id
 		err = ioctl.Ioctl(fd, VIDIOC_QUERYCTRL, uintptr(unsafe.Pointer(query)))
@@ -528,26 +1144,88 @@ func queryControls(fd uintptr) []control {
 				continue
 			}
 			var c control
-			switch query._type {
-			default:
-				continue
-			case V4L2_CTRL_TYPE_INTEGER, V4L2_CTRL_TYPE_INTEGER64:
-				c.c_type = c_int
-			case V4L2_CTRL_TYPE_BOOLEAN:
-				c.c_type = c_bool
-			case V4L2_CTRL_TYPE_MENU:
-				c.c_type = c_menu
-			}
 			c.id = id
 			c.name = CToGoString(query.name[:])
+			c.c_type = query._type
 			c.min = query.minimum
 			c.max = query.maximum
+			c.step = query.step
+			c.def = query.default_value
+			c.flags = query.flags
+			if c.c_type == V4L2_CTRL_TYPE_MENU || c.c_type == V4L2_CTRL_TYPE_INTEGER_MENU {
+				c.menu = queryMenu(fd, c.id, c.c_type, c.min, c.max)
+			}
 			controls = append(controls, c)
 		}
 	}
 	return controls
 }
 
+// Same as queryControls, but returns unix.ENOTTY
+// if VIDIOC_QUERY_EXT_CTRL is not supported
+func queryExtControls(fd uintptr) ([]control, error) {
+	controls := []control{}
+	var id uint32
+	for {
+		query, err := queryExtControl(fd, id|V4L2_CTRL_FLAG_NEXT_CTRL|V4L2_CTRL_FLAG_NEXT_COMPOUND)
+		if err == unix.ENOTTY {
+			return nil, err
+		}
+		if err != nil {
+			return controls, nil
+		}
+		id = query.id
+		if (query.flags & V4L2_CTRL_FLAG_DISABLED) != 0 {
+			continue
+		}
+		var c control
+		c.id = id
+		c.name = CToGoString(query.name[:])
+		c.c_type = query._type
+		c.min = clampInt32(query.minimum)
+		c.max = clampInt32(query.maximum)
+		c.step = clampInt32(int64(query.step))
+		c.def = clampInt32(query.default_value)
+		c.flags = query.flags
+		if c.c_type == V4L2_CTRL_TYPE_MENU || c.c_type == V4L2_CTRL_TYPE_INTEGER_MENU {
+			c.menu = queryMenu(fd, c.id, c.c_type, c.min, c.max)
+		}
+		controls = append(controls, c)
+	}
+}
+
+func clampInt32(v int64) int32 {
+	if v > math.MaxInt32 {
+		return math.MaxInt32
+	}
+	if v < math.MinInt32 {
+		return math.MinInt32
+	}
+	return int32(v)
+}
+
+func queryMenu(fd uintptr, id uint32, c_type uint32, min, max int32) []ControlMenuItem {
+	items := []ControlMenuItem{}
+	for index := min; index <= max && index >= 0; index++ {
+		query := &v4l2_querymenu{}
+		query.id = id
+		query.index = uint32(index)
+		// Drivers may skip unsupported items
+		if ioctl.Ioctl(fd, VIDIOC_QUERYMENU, uintptr(unsafe.Pointer(query))) != nil {
+			continue
+		}
+		item := ControlMenuItem{Index: query.index}
+		if c_type == V4L2_CTRL_TYPE_INTEGER_MENU {
+			item.Value = int64(NativeByteOrder.Uint64(query.union[:8]))
+			item.Name = fmt.Sprintf("%d", item.Value)
+		} else {
+			item.Name = CToGoString(query.union[:])
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
 func getNativeByteOrder() binary.ByteOrder {
 	var i int32 = 0x01020304
 	u := unsafe.Pointer(&i)
@@ -0,0 +1,132 @@
+package webcam
+
+import "fmt"
+
+// Defines how control values are checked before they are sent to the driver
+type ControlValidation int
+
+const (
+	// Values are passed to the driver as is.
+	// Drivers either clamp out of range values or reject them with ERANGE
+	ControlValidationNone ControlValidation = iota
+
+	// Values out of range, not matching the step or not present in menu
+	// are refused with ControlValueError
+	ControlValidationStrict
+
+	// Values are clamped to range, rounded to the nearest step
+	// or to the nearest menu item
+	ControlValidationClamp
+)
+
+// Set how SetControl and named control accessors check values.
+// In strict and clamp modes writes to read-only and inactive
+// controls are refused with ControlValueError
+func (w *Webcam) SetControlValidation(mode ControlValidation) {
+	w.validation = mode
+}
+
+// Set a control and return the value the driver actually applied.
+// Value is checked according to mode set by SetControlValidation
+func (w *Webcam) ApplyControl(id ControlID, value int32) (int32, error) {
+	if w.validation != ControlValidationNone {
+		c, err := queryControl(w.fd, uint32(id))
+		if err != nil || (c.flags&V4L2_CTRL_FLAG_DISABLED) != 0 {
+			return 0, ErrControlUnsupported
+		}
+		value, err = c.validate(value, w.validation == ControlValidationClamp)
+		if err != nil {
+			return 0, err
+		}
+	}
+	return setControlValue(w.fd, uint32(id), value)
+}
+
+func (c control) validate(value int32, clamp bool) (int32, error) {
+	refuse := func(reason string, args ...interface{}) (int32, error) {
+		return 0, &ControlValueError{ControlID(c.id), c.name, value, fmt.Sprintf(reason, args...)}
+	}
+
+	if (c.flags & V4L2_CTRL_FLAG_READ_ONLY) != 0 {
+		return refuse("control is read-only")
+	}
+	if (c.flags & V4L2_CTRL_FLAG_INACTIVE) != 0 {
+		return refuse("control is inactive")
+	}
+
+	switch c.c_type {
+	case V4L2_CTRL_TYPE_BUTTON, V4L2_CTRL_TYPE_INTEGER64:
+		// Legacy query doesn't report 64-bit ranges
+		return value, nil
+
+	case V4L2_CTRL_TYPE_BITMASK:
+		if uint32(value)&^uint32(c.max) != 0 {
+			if !clamp {
+				return refuse("bits outside of mask %#x", uint32(c.max))
+			}
+			value = int32(uint32(value) & uint32(c.max))
+		}
+		return value, nil
+
+	case V4L2_CTRL_TYPE_MENU, V4L2_CTRL_TYPE_INTEGER_MENU:
+		return c.validateMenu(value, clamp, refuse)
+	}
+
+	if value < c.min || value > c.max {
+		if !clamp {
+			return refuse("value out of range [%d, %d]", c.min, c.max)
+		}
+		if value < c.min {
+			value = c.min
+		} else {
+			value = c.max
+		}
+	}
+
+	if c.step > 1 {
+		offset := int64(value) - int64(c.min)
+		if offset%int64(c.step) != 0 {
+			if !clamp {
+				return refuse("value doesn't match step %d from %d", c.step, c.min)
+			}
+			rounded := int64(c.min) + (offset+int64(c.step)/2)/int64(c.step)*int64(c.step)
+			if rounded > int64(c.max) {
+				rounded -= int64(c.step)
+			}
+			value = int32(rounded)
+		}
+	}
+
+	return value, nil
+}
+
+// Menu values are indexes of items, drivers may skip some of them
+func (c control) validateMenu(value int32, clamp bool, refuse func(string, ...interface{}) (int32, error)) (int32, error) {
+	if len(c.menu) == 0 {
+		return refuse("menu has no items")
+	}
+
+	nearest := int32(c.menu[0].Index)
+	for _, item := range c.menu {
+		index := int32(item.Index)
+		if index == value {
+			return value, nil
+		}
+		// Differences of int32 values may overflow int32
+		if abs64(int64(index)-int64(value)) < abs64(int64(nearest)-int64(value)) {
+			nearest = index
+		}
+	}
+
+	if !clamp {
+		return refuse("no such menu item")
+	}
+	return nearest, nil
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
@@ -6,6 +6,7 @@ package webcam
 import (
 	"errors"
 	"reflect"
+	"sync"
 	"unsafe"
 
 	"golang.org/x/sys/unix"
@@ -13,18 +14,122 @@ import (
 
 // Webcam object
 type Webcam struct {
-	fd        uintptr
-	bufcount  uint32
-	buffers   [][]byte
-	streaming bool
+	fd         uintptr
+	bufcount   uint32
+	buffers    [][]byte
+	streaming  bool
+	validation ControlValidation
+
+	eventsLock    sync.Mutex
+	controlEvents chan ControlEvent
+	streamEvents  chan StreamEvent
+	droppedEvents uint64
+	autoRestart   bool
+	sourceChange  *StreamEvent
+	preferences   *FormatPreferences
+	fallbackMode  *modeCandidate
+
+	frameValidation FrameValidation
+	frameFormat     FrameFormat
+	stats           StreamStats
 }
 
 type ControlID uint32
 
+// Description of a control.
+// Type is one of V4L2_CTRL_TYPE_* values and Flags is a combination
+// of V4L2_CTRL_FLAG_* values. Menu is set for menu and integer menu
+// controls only
 type Control struct {
-	Name string
-	Min  int32
-	Max  int32
+	Name    string
+	Type    uint32
+	Min     int32
+	Max     int32
+	Step    int32
+	Default int32
+	Flags   uint32
+	Menu    []ControlMenuItem
+}
+
+// Control together with its ID
+type ControlEntry struct {
+	ID ControlID
+	Control
+}
+
+// Group of controls of the same class.
+// ID is one of V4L2_CTRL_CLASS_* values
+type ControlClass struct {
+	ID       uint32
+	Name     string
+	Controls []ControlEntry
+}
+
+// Names used for classes the driver doesn't describe itself
+var controlClassNames = map[uint32]string{
+	V4L2_CTRL_CLASS_USER:            "User Controls",
+	V4L2_CTRL_CLASS_CODEC:           "Codec Controls",
+	V4L2_CTRL_CLASS_CAMERA:          "Camera Controls",
+	V4L2_CTRL_CLASS_FM_TX:           "FM Radio Modulator Controls",
+	V4L2_CTRL_CLASS_FLASH:           "Flash Controls",
+	V4L2_CTRL_CLASS_JPEG:            "JPEG Compression Controls",
+	V4L2_CTRL_CLASS_IMAGE_SOURCE:    "Image Source Controls",
+	V4L2_CTRL_CLASS_IMAGE_PROC:      "Image Processing Controls",
+	V4L2_CTRL_CLASS_DV:              "Digital Video Controls",
+	V4L2_CTRL_CLASS_FM_RX:           "FM Radio Receiver Controls",
+	V4L2_CTRL_CLASS_RF_TUNER:        "RF Tuner Controls",
+	V4L2_CTRL_CLASS_DETECT:          "Detection Controls",
+	V4L2_CTRL_CLASS_CODEC_STATELESS: "Stateless Codec Controls",
+	V4L2_CTRL_CLASS_COLORIMETRY:     "Colorimetry Controls",
+}
+
+// Item of a menu control.
+// For integer menu controls Value holds the item value
+// and Name is its string representation
+type ControlMenuItem struct {
+	Index uint32
+	Name  string
+	Value int64
+}
+
+// Control value can't be changed
+func (c Control) ReadOnly() bool {
+	return (c.Flags & V4L2_CTRL_FLAG_READ_ONLY) != 0
+}
+
+// Control value can't be read, e.g. buttons
+func (c Control) WriteOnly() bool {
+	return (c.Flags & V4L2_CTRL_FLAG_WRITE_ONLY) != 0
+}
+
+// Control doesn't take effect at the moment, e.g. manual
+// exposure while auto exposure is on
+func (c Control) Inactive() bool {
+	return (c.Flags & V4L2_CTRL_FLAG_INACTIVE) != 0
+}
+
+// Control value is changed by the device itself
+func (c Control) Volatile() bool {
+	return (c.Flags & V4L2_CTRL_FLAG_VOLATILE) != 0
+}
+
+// Control is temporarily locked, e.g. while streaming
+func (c Control) Grabbed() bool {
+	return (c.Flags & V4L2_CTRL_FLAG_GRABBED) != 0
+}
+
+// Changing the control may affect other controls
+func (c Control) Update() bool {
+	return (c.Flags & V4L2_CTRL_FLAG_UPDATE) != 0
+}
+
+// Video input of a device
+type Input struct {
+	Index        uint32
+	Name         string
+	Type         uint32
+	Status       uint32
+	Capabilities uint32
 }
 
 // Open a webcam with a given path
@@ -64,23 +169,36 @@ func Open(path string) (*Webcam, error) {
 // Not that this function is somewhat experimental. Frames are not ordered in
 // any meaning, also duplicates can occur so it's up to developer to clean it up.
 // See http://linuxtv.org/downloads/v4l-dvb-apis/vidioc-enum-framesizes.html
-// for more information
+// for more information.
+// See GetFormatDescriptions for formats in driver order with their flags
 func (w *Webcam) GetSupportedFormats() map[PixelFormat]string {
 
 	result := make(map[PixelFormat]string)
+	for _, d := range w.GetFormatDescriptions() {
+		result[d.Format] = d.Description
+	}
+
+	return result
+}
+
+// Returns image formats supported by the device in the order reported
+// by the driver, which usually lists preferred formats first
+func (w *Webcam) GetFormatDescriptions() []FormatDescription {
+
+	result := make([]FormatDescription, 0)
 	var err error
-	var code uint32
+	var code, flags uint32
 	var desc string
 	var index uint32
 
 	for index = 0; err == nil; index++ {
-		code, desc, err = getPixelFormat(w.fd, index)
+		code, desc, flags, err = getPixelFormat(w.fd, index)
 
 		if err != nil {
 			break
 		}
 
-		result[PixelFormat(code)] = desc
+		result = append(result, FormatDescription{PixelFormat(code), desc, flags})
 	}
 
 	return result
@@ -106,23 +224,116 @@ func (w *Webcam) GetSupportedFrameSizes(f PixelFormat) []FrameSize {
 	return result
 }
 
+// Returns supported frame intervals for a given image format and frame size.
+// Discrete intervals are returned one per entry, stepwise and continuous
+// ranges are returned as a single entry.
+// See http://linuxtv.org/downloads/v4l-dvb-apis/vidioc-enum-frameintervals.html
+// for more information
+func (w *Webcam) GetSupportedFrameIntervals(f PixelFormat, width, height uint32) []FrameInterval {
+	result := make([]FrameInterval, 0)
+
+	var index uint32
+
+	for index = 0; ; index++ {
+		i, err := getFrameInterval(w.fd, index, uint32(f), width, height)
+
+		if err != nil {
+			break
+		}
+
+		result = append(result, i)
+	}
+
+	return result
+}
+
+// Returns current image format and frame size
+func (w *Webcam) GetImageFormat() (PixelFormat, uint32, uint32, error) {
+	var code, width, height uint32
+
+	err := getImageFormat(w.fd, &code, &width, &height)
+
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return PixelFormat(code), width, height, nil
+}
+
 // Sets desired image format and frame size
 // Note, that device driver can change that values.
 // Resulting values are returned by a function
-// alongside with an error if any
+// alongside with an error if any. Frame size
+// is the one adjusted by the driver, not the requested one
 func (w *Webcam) SetImageFormat(f PixelFormat, width, height uint32) (PixelFormat, uint32, uint32, error) {
 
 	code := uint32(f)
-	cw := width
-	ch := height
 
 	err := setImageFormat(w.fd, &code, &width, &height)
 
 	if err != nil {
 		return 0, 0, 0, err
 	} else {
-		return PixelFormat(code), cw, ch, nil
+		return PixelFormat(code), width, height, nil
+	}
+}
+
+// Returns current image format together with its memory layout,
+// see FrameFormat
+func (w *Webcam) GetFrameFormat() (FrameFormat, error) {
+	pix := &v4l2_pix_format{}
+	err := pixFormat(w.fd, VIDIOC_G_FMT, pix)
+	if err != nil {
+		return FrameFormat{}, err
+	}
+	f := FrameFormat{
+		Format:       PixelFormat(pix.Pixelformat),
+		Width:        pix.Width,
+		Height:       pix.Height,
+		BytesPerLine: pix.Bytesperline,
+		SizeImage:    pix.Sizeimage,
+		Colorspace:   pix.Colorspace,
+	}
+	// Older drivers leave fields following priv undefined
+	if pix.Priv == V4L2_PIX_FMT_PRIV_MAGIC {
+		f.YCbCrEncoding = pix.Ycbcr_enc
+		f.Quantization = pix.Quantization
+		f.XferFunc = pix.Xfer_func
+	}
+	return f, nil
+}
+
+// Returns video inputs of the device, e.g. camera sensors
+// or connectors of a capture card
+func (w *Webcam) GetInputs() []Input {
+	result := make([]Input, 0)
+
+	var index uint32
+
+	for index = 0; ; index++ {
+		i, err := getInput(w.fd, index)
+
+		if err != nil {
+			break
+		}
+
+		result = append(result, i)
+	}
+
+	return result
+}
+
+// Returns index of the current video input
+func (w *Webcam) GetInput() (uint32, error) {
+	return getCurrentInput(w.fd)
+}
+
+// Selects video input by its index.
+// Not allowed if streaming is already on
+func (w *Webcam) SetInput(index uint32) error {
+	if w.streaming {
+		return errors.New("Cannot change input when streaming")
 	}
+	return setCurrentInput(w.fd, index)
 }
 
 // Set the number of frames to be buffered.
@@ -136,22 +347,62 @@ func (w *Webcam) SetBufferCount(count uint32) error {
 }
 
 // Get a map of available controls.
-func (w *Webcam) GetControls() map[ControlID]Control {
+//
+// Deprecated: the map loses the order and classes of controls,
+// use GetControls instead
+func (w *Webcam) GetControlsMap() map[ControlID]Control {
 	cmap := make(map[ControlID]Control)
-	for _, c := range queryControls(w.fd) {
-		cmap[ControlID(c.id)] = Control{c.name, c.min, c.max}
+	for _, class := range w.GetControls() {
+		for _, c := range class.Controls {
+			cmap[c.ID] = c.Control
+		}
 	}
 	return cmap
 }
 
+// Get available controls grouped by class (user, camera, JPEG, etc.)
+// Classes and controls inside a class are ordered the same way as
+// the driver reports them, so they can be rendered as settings panels.
+func (w *Webcam) GetControls() []ControlClass {
+	classes := []ControlClass{}
+	for _, c := range queryControls(w.fd) {
+		class := c.id & V4L2_CTRL_CLASS_MASK
+		if len(classes) == 0 || classes[len(classes)-1].ID != class {
+			classes = append(classes, ControlClass{ID: class, Name: controlClassNames[class]})
+		}
+		last := &classes[len(classes)-1]
+		if c.c_type == V4L2_CTRL_TYPE_CTRL_CLASS {
+			last.Name = c.name
+			continue
+		}
+		last.Controls = append(last.Controls, ControlEntry{ControlID(c.id), c.export()})
+	}
+	return classes
+}
+
+func (c control) export() Control {
+	return Control{
+		Name:    c.name,
+		Type:    c.c_type,
+		Min:     c.min,
+		Max:     c.max,
+		Step:    c.step,
+		Default: c.def,
+		Flags:   c.flags,
+		Menu:    c.menu,
+	}
+}
+
 // Get the value of a control.
 func (w *Webcam) GetControl(id ControlID) (int32, error) {
 	return getControl(w.fd, uint32(id))
 }
 
 // Set a control.
+// Value is checked according to mode set by SetControlValidation
 func (w *Webcam) SetControl(id ControlID, value int32) error {
-	return setControl(w.fd, uint32(id), value)
+	_, err := w.ApplyControl(id, value)
+	return err
 }
 
 // Get the framerate.
@@ -160,8 +411,34 @@ func (w *Webcam) GetFramerate() (float32, error) {
 }
 
 // Set FPS
+// Note that the value is rounded to 1/1000 of a frame, use
+// SetFrameInterval to set NTSC rates like 30000/1001 exactly
 func (w *Webcam) SetFramerate(fps float32) error {
-	return setFramerate(w.fd, 1000, uint32(1000*(fps)))
+	_, err := w.SetFrameInterval(1000, uint32(1000*(fps)))
+	return err
+}
+
+// Get the time between frames as a fraction of seconds,
+// e.g. 1/30 for 30 FPS
+func (w *Webcam) GetFrameInterval() (Fraction, error) {
+	return getTimePerFrame(w.fd)
+}
+
+// Sets the time between frames to num/denom seconds.
+// Note, that device driver can change that value.
+// Resulting interval is returned by a function
+// alongside with an error if any.
+// Returns FramerateUnsupported error if device
+// does not support framerate control
+func (w *Webcam) SetFrameInterval(num, denom uint32) (Fraction, error) {
+	supported, err := supportsTimePerFrame(w.fd)
+	if err != nil {
+		return Fraction{}, err
+	}
+	if !supported {
+		return Fraction{}, new(FramerateUnsupported)
+	}
+	return setTimePerFrame(w.fd, num, denom)
 }
 
 // Start streaming process
@@ -199,22 +476,41 @@ func (w *Webcam) StartStreaming() error {
 
 	}
 
+	// Frames are validated against the format they were captured in
+	w.frameFormat, _ = w.GetFrameFormat()
+
 	err = startStreaming(w.fd)
 
 	if err != nil {
-		return errors.New("Failed to start streaming: " + string(err.Error()))
+		// Free buffers, so image format can be changed before next attempt
+		w.releaseBuffers()
+		return &StreamingError{err}
 	}
 	w.streaming = true
+	w.stats = StreamStats{}
 
 	return nil
 }
 
+func (w *Webcam) releaseBuffers() error {
+	for _, buffer := range w.buffers {
+		err := mmapReleaseBuffer(buffer)
+		if err != nil {
+			return err
+		}
+	}
+	w.buffers = nil
+
+	var count uint32
+	return mmapRequestBuffers(w.fd, &count)
+}
+
 // Read a single frame from the webcam
 // If frame cannot be read at the moment
 // function will return empty slice
 func (w *Webcam) ReadFrame() ([]byte, error) {
 	result, index, err := w.GetFrame()
-	if err == nil {
+	if _, corrupt := err.(*CorruptFrame); err == nil || corrupt {
 		w.ReleaseFrame(index)
 	}
 	return result, err
@@ -223,18 +519,28 @@ func (w *Webcam) ReadFrame() ([]byte, error) {
 // Get a single frame from the webcam and return the frame and
 // the buffer index. To return the buffer, ReleaseFrame must be called.
 // If frame cannot be read at the moment
-// function will return empty slice
+// function will return empty slice.
+// Damaged frames are handled according to SetFrameValidation: they are
+// returned with CorruptFrame error and must be released too, or dropped,
+// in which case empty slice is returned and there is nothing to release
 func (w *Webcam) GetFrame() ([]byte, uint32, error) {
 	var index uint32
 	var length uint32
+	var flags uint32
 
-	err := mmapDequeueBuffer(w.fd, &index, &length)
+	err := mmapDequeueBuffer(w.fd, &index, &length, &flags)
 
 	if err != nil {
 		return nil, 0, err
 	}
 
-	return w.buffers[int(index)][:length], index, nil
+	frame := w.buffers[int(index)][:length]
+	drop, err := w.checkFrame(frame, index, flags)
+	if drop {
+		return nil, 0, mmapEnqueueBuffer(w.fd, index)
+	}
+
+	return frame, index, err
 
 }
 
@@ -243,10 +549,28 @@ func (w *Webcam) ReleaseFrame(index uint32) error {
 	return mmapEnqueueBuffer(w.fd, index)
 }
 
-// Wait until frame could be read
+// Wait until frame could be read.
+// Events the webcam is subscribed to are dequeued while waiting.
+// If automatic restart on source change is enabled, streaming
+// is restarted here, so buffers obtained by GetFrame become invalid.
+// If the restart fails, its error is returned and the device
+// is left not streaming
 func (w *Webcam) WaitForFrame(timeout uint32) error {
 
-	count, err := waitForFrame(w.fd, timeout)
+	var onEvent func() bool
+	if w.hasEventSubscribers() {
+		onEvent = w.dequeueEvents
+	}
+
+	count, err := waitForFrame(w.fd, timeout, onEvent)
+
+	for w.hasSourceChange() {
+		// Source has changed and automatic restart was requested
+		if err := w.restartAfterSourceChange(); err != nil {
+			return err
+		}
+		count, err = waitForFrame(w.fd, timeout, onEvent)
+	}
 
 	if count < 0 || err != nil {
 		return err
@@ -272,7 +596,10 @@ func (w *Webcam) StopStreaming() error {
 	return stopStreaming(w.fd)
 }
 
-// Close the device
+// Close the device.
+// Event channels are closed too. Close must not be called while
+// WaitForFrame, GetFrame or ReadFrame run in another goroutine,
+// as frame buffers are unmapped and the device is closed
 func (w *Webcam) Close() error {
 	if w.streaming {
 		w.StopStreaming()
@@ -280,16 +607,9 @@ func (w *Webcam) Close() error {
 
 	err := unix.Close(int(w.fd))
 
-	return err
-}
+	w.closeEvents()
 
-// Sets automatic white balance correction
-func (w *Webcam) SetAutoWhiteBalance(val bool) error {
-	v := int32(0)
-	if val {
-		v = 1
-	}
-	return setControl(w.fd, V4L2_CID_AUTO_WHITE_BALANCE, v)
+	return err
 }
 
 func gobytes(p unsafe.Pointer, n int) []byte {